@@ -10,7 +10,8 @@ import (
 )
 
 // NewCommand returns root level command.
-// Supports `--version`.
+// Supports `--version` and `--print-template`.
+// With `--prune`, removes stale output files instead of generating them.
 // Default is to generate markdown from current directory.
 func NewCommand(writer io.WriteCloser, version string) *cobra.Command {
 	cmd := &cobra.Command{
@@ -20,24 +21,74 @@ func NewCommand(writer io.WriteCloser, version string) *cobra.Command {
 				_, _ = writer.Write([]byte(fmt.Sprintf("go2md %s\n", version)))
 				return nil
 			}
+			if flag, _ := cmd.Flags().GetBool("print-template"); flag {
+				_, _ = writer.Write([]byte(pkg.Markdown))
+				return nil
+			}
+			all, _ := cmd.Flags().GetBool("all")
+			check, _ := cmd.Flags().GetBool("check")
+			collapseDeprecated, _ := cmd.Flags().GetBool("collapse-deprecated")
+			combined, _ := cmd.Flags().GetBool("combined")
 			dir, _ := cmd.Flags().GetString("directory")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			expandInterfaces, _ := cmd.Flags().GetBool("expand-interfaces")
+			fieldTable, _ := cmd.Flags().GetBool("field-table")
+			force, _ := cmd.Flags().GetBool("force")
 			ignoreMain, _ := cmd.Flags().GetBool("ignore-main")
+			index, _ := cmd.Flags().GetString("index")
+			indexInternal, _ := cmd.Flags().GetString("index-internal")
+			indexMain, _ := cmd.Flags().GetString("index-main")
+			markers, _ := cmd.Flags().GetBool("markers")
+			noteMarkers, _ := cmd.Flags().GetStringSlice("note-markers")
 			output, _ := cmd.Flags().GetString("output")
+			outputDir, _ := cmd.Flags().GetString("output-dir")
+			prune, _ := cmd.Flags().GetBool("prune")
 			recursive, _ := cmd.Flags().GetBool("recursive")
+			tmpl, _ := cmd.Flags().GetString("template")
 			// execute
-			outInput := pkg.OutputSettings{Default: writer, Directory: dir, Filename: output}
+			outInput := pkg.OutputSettings{
+				Default: writer, Directory: dir, Filename: output, OutputDir: outputDir, Template: tmpl,
+				Check: check, Markers: markers, Force: force, Combined: combined, Index: index, FieldTable: fieldTable,
+				IndexInternal: pkg.IndexMode(indexInternal), IndexMain: pkg.IndexMode(indexMain),
+				ExpandInterfaces: expandInterfaces, CollapseDeprecated: collapseDeprecated, NoteMarkers: noteMarkers,
+				All: all,
+			}
+			if prune {
+				stale, err := pkg.PruneDirTree(outInput, dryRun)
+				for _, fname := range stale {
+					_, _ = writer.Write([]byte(fname + "\n"))
+				}
+				return err
+			}
 			if recursive {
 				return pkg.RunDirTree(outInput, version, !ignoreMain)
 			}
 			return pkg.RunDirectory(outInput, version, !ignoreMain)
 		},
 	}
+	cmd.Flags().Bool("all", false, "document also unexported identifiers")
+	cmd.Flags().Bool("check", false, "fail with diff, if output file is outdated")
+	cmd.Flags().Bool("collapse-deprecated", false, "collapse docs of deprecated symbols")
+	cmd.Flags().Bool("combined", false, "write all packages into one document with --recursive")
 	cmd.Flags().StringP("directory", "d", ".", "root directory")
-	cmd.Flags().StringP("output", "o", "", "write output to file")
+	cmd.Flags().StringP("output", "o", "", "write output to file (can be template, e.g. {{.Name}}.md)")
+	cmd.Flags().String("output-dir", "", "mirror output files into separate directory tree")
 	cmd.Flags().Bool("debug", false, "debug level logging")
+	cmd.Flags().Bool("dry-run", false, "only list files that --prune would remove")
+	cmd.Flags().Bool("expand-interfaces", false, "list methods of embedded interfaces under interface types")
+	cmd.Flags().Bool("field-table", false, "render table of struct fields with their tags and docs")
+	cmd.Flags().Bool("force", false, "overwrite output files, which were not generated by go2md")
 	cmd.Flags().Bool("ignore-main", false, "ignore directory, if its main package")
+	cmd.Flags().String("index", "", "write module index into given file with --recursive")
+	cmd.Flags().String("index-internal", string(pkg.IndexMark), "show internal packages in index (include, mark or exclude)")
+	cmd.Flags().String("index-main", string(pkg.IndexMark), "show main packages in index (include, mark or exclude)")
+	cmd.Flags().Bool("markers", false, "replace only content between go2md markers in output file")
 	cmd.Flags().Bool("no-color", false, "don't use ANSI colors in logging")
+	cmd.Flags().StringSlice("note-markers", nil, "render notes with these markers (e.g. TODO,SECURITY,PERF) in addition to BUG")
+	cmd.Flags().Bool("prune", false, "remove generated output files from directories without golang package")
+	cmd.Flags().Bool("print-template", false, "print built-in template and exit")
 	cmd.Flags().BoolP("recursive", "r", false, "go directories recursively")
+	cmd.Flags().StringP("template", "t", "", "read output template from file")
 	cmd.Flags().BoolP("version", "v", false, "print go2md version")
 	return cmd
 }
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/jylitalo/go2md/pkg"
 )
 
 type writeCloser struct {
@@ -47,6 +49,19 @@ func TestNewCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("print template", func(t *testing.T) {
+		var wc writeCloser
+
+		cmd := NewCommand(&wc, "test")
+		cmd.SetArgs([]string{"--print-template"})
+		if err := cmd.Execute(); err != nil {
+			t.Error("Run() returned err: " + err.Error())
+		}
+		if wc.String() != pkg.Markdown {
+			t.Error("--print-template output doesn't match pkg.Markdown")
+		}
+	})
+
 	t.Run("ignore main without recursive", func(t *testing.T) {
 		var wc writeCloser
 
@@ -118,7 +133,7 @@ func TestNewCommand(t *testing.T) {
 		if err = cmd.Execute(); err != nil {
 			t.Error("Run() returned err: " + err.Error())
 		}
-		expected := strings.TrimSpace(string(readme))
+		expected := strings.TrimSpace(strings.TrimPrefix(string(readme), pkg.GeneratedHeader+"\n"))
 		received := strings.TrimSpace(wc.String())
 		if expected != received {
 			t.Error("outputs don't match")
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"slices"
+	"strings"
 
 	"github.com/jylitalo/go2md/cmd"
 	"github.com/jylitalo/tint"
@@ -16,7 +17,7 @@ import (
 var Version string // value from version.txt file
 
 func execute(writer io.WriteCloser) error {
-	return cmd.NewCommand(writer, Version).Execute()
+	return cmd.NewCommand(writer, strings.TrimSpace(Version)).Execute()
 }
 
 func setupLogging(debug bool, color bool) {
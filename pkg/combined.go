@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	htmlTag      = regexp.MustCompile(`<[^>]*>`)
+	anchorRef    = regexp.MustCompile(`(\]\(#|href="#)([^)"]+)`)
+)
+
+// anchorPrefix turns import path into prefix, which makes anchors unique within combined document.
+func anchorPrefix(importPath string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, importPath)
+}
+
+// headingSlug creates anchor from heading text the same way as GitHub does it.
+func headingSlug(heading string) string {
+	heading = markdownLink.ReplaceAllString(heading, "$1")
+	heading = htmlTag.ReplaceAllString(heading, "")
+	return intoLink(strings.TrimSpace(heading))
+}
+
+// combineSection shifts headings in package documentation one level down and
+// makes their anchors unique by prefixing them.
+// First heading (package title) gets prefix as its anchor.
+// Links to local anchors are updated to point into prefixed anchors.
+func combineSection(content, prefix string) string {
+	lines := strings.Split(content, "\n")
+	anchors := map[string]string{}
+	headings := map[int]string{}
+	seen := map[string]int{}
+	inCode := false
+	for idx, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "```"):
+			inCode = !inCode
+		case strings.HasPrefix(line, "<pre>"):
+			inCode = !strings.Contains(line, "</pre>")
+		case strings.HasPrefix(line, "</pre>"):
+			inCode = false
+		case !inCode && strings.HasPrefix(line, "#"):
+			slug := headingSlug(strings.TrimLeft(line, "#"))
+			// duplicate headings are numbered like GitHub does it (see getAnchors)
+			if count := seen[slug]; count > 0 {
+				seen[slug]++
+				slug = fmt.Sprintf("%s-%d", slug, count)
+			} else {
+				seen[slug]++
+			}
+			anchor := prefix + "-" + slug
+			if len(headings) == 0 {
+				anchor = prefix
+			}
+			anchors[slug] = anchor
+			headings[idx] = anchor
+		}
+	}
+	section := []string{}
+	for idx, line := range lines {
+		line = anchorRef.ReplaceAllStringFunc(line, func(ref string) string {
+			match := anchorRef.FindStringSubmatch(ref)
+			if anchor, ok := anchors[match[2]]; ok {
+				return match[1] + anchor
+			}
+			return ref
+		})
+		if anchor, ok := headings[idx]; ok {
+			section = append(section, fmt.Sprintf(`<a name="%s"></a>`, anchor))
+			line = "#" + line
+		}
+		section = append(section, line)
+	}
+	return strings.Join(section, "\n")
+}
+
+// combinedPath returns absolute path to combined document. It is placed like output file of
+// package in Directory would be, so OutputDir and Filename template are followed.
+func (output *OutputSettings) combinedPath() (string, error) {
+	importPath, mod, err := getPackageName(output.Directory)
+	if err != nil {
+		return "", fmt.Errorf("OutputSettings.combinedPath failed: %w", err)
+	}
+	return output.outputPath(mod, importPath)
+}
+
+// runCombined renders packages from paths into one document, which starts with module level
+// table of contents and ends with one footer.
+func runCombined(out OutputSettings, version string, includeMain bool, paths []string, importers sourceImporters) error {
+	root := out.Directory
+	combinedFile, err := out.combinedPath()
+	if err != nil {
+		return err
+	}
+	out.combinedFile = combinedFile
+	var last *packageOutput
+	toc := []string{}
+	sections := []string{}
+	for _, path := range paths {
+		out.Directory = path
+		pkgName, mod, err := getPackageName(path)
+		if err != nil {
+			return err
+		}
+		importers.forModule(mod)
+		pkgOut, err := render(out, mod, pkgName, version, "section", includeMain)
+		switch {
+		case errors.Is(err, ErrNoPackageFound):
+			slog.Warn("failed to find package from " + path)
+			continue
+		case err != nil:
+			return err
+		case pkgOut == nil:
+			continue
+		}
+		prefix := anchorPrefix(pkgName)
+		toc = append(toc, fmt.Sprintf("- [%s](#%s)", pkgName, prefix))
+		sections = append(sections, combineSection(string(pkgOut.content), prefix))
+		last = pkgOut
+	}
+	if last == nil {
+		return fmt.Errorf("%w %s", ErrNoPackageFound, root)
+	}
+	footer := bytes.Buffer{}
+	if err = last.tmpl.ExecuteTemplate(&footer, "footer", last.data); err != nil {
+		return fmt.Errorf("tmpl.Execute failed: %w", err)
+	}
+	content := fmt.Sprintf(
+		"# %s\n\n## Packages\n%s\n\n%s\n\n%s\n",
+		last.data.ModulePath, strings.Join(toc, "\n"), strings.Join(sections, "\n\n"), footer.String(),
+	)
+	out.Directory = root
+	if out.Filename != "" {
+		out.Directory, out.Filename = filepath.Split(combinedFile)
+	}
+	return out.save([]byte(content))
+}
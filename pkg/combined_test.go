@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestCombined verifies that all packages are written into one document with unique anchors.
+func TestCombined(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a is for testing.\npackage a\n\n// T is a type.\ntype T struct{}\n",
+		"b/b.go": "// Package b is for testing.\npackage b\n\nimport \"example.com/mod/a\"\n\n" +
+			"// T is a type.\ntype T struct{}\n\n// New returns a.T.\nfunc New() a.T { return a.T{} }\n",
+	})
+	var wc writeCloser
+	out := OutputSettings{Default: &wc, Directory: dir, Combined: true}
+	if err := RunDirTree(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"# example.com/mod\n\n## Packages\n- [example.com/mod/a](#example-com-mod-a)\n- [example.com/mod/b](#example-com-mod-b)\n",
+		"<a name=\"example-com-mod-a\"></a>\n## example.com/mod/a\n",
+		"<a name=\"example-com-mod-a-type-t\"></a>\n#### type [T](a/a.go#L5)\n",
+		"<a name=\"example-com-mod-b-type-t\"></a>\n#### type [T](b/b.go#L7)\n",
+		"<a name=\"example-com-mod-b-func-new\"></a>\n#### func [New](b/b.go#L10)\n",
+		"- [type T](#example-com-mod-b-type-t)",
+		`<a href="#example-com-mod-a-type-t">a.T</a>`,
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+	if strings.Count(received, "Generated by") != 1 {
+		t.Errorf("combined document should have one footer: %s", received)
+	}
+	// combined document follows OutputDir like other output files
+	out = OutputSettings{Directory: dir, OutputDir: dir + "/docs", Filename: "API.md", Combined: true}
+	if err := RunDirTree(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(dir + "/docs/API.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "#### type [T](../a/a.go#L5)\n"; !strings.Contains(string(content), expected) {
+		t.Errorf("%#v is missing from %s", expected, string(content))
+	}
+	if fileExists(dir + "/API.md") {
+		t.Error("combined document was written into Directory instead of OutputDir")
+	}
+	// without RunDirTree, there is no combined document to point anchors into
+	wc = writeCloser{}
+	out = OutputSettings{Default: &wc, Directory: dir + "/b", Combined: true}
+	if err = RunDirectory(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(wc.String(), "#example-com-mod-a") {
+		t.Errorf("single package has anchors of combined document: %s", wc.String())
+	}
+	// template, which replaces whole output, renders each package
+	wc = writeCloser{}
+	out = OutputSettings{Default: &wc, Directory: dir, Template: dir + "/custom.tmpl", Combined: true}
+	if err = os.WriteFile(out.Template, []byte("# custom {{ .Name }}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = RunDirTree(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"## custom example.com/mod/a\n", "## custom example.com/mod/b\n", "Generated by"} {
+		if !strings.Contains(wc.String(), expected) {
+			t.Errorf("%#v is missing from %s", expected, wc.String())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"go/doc/comment"
+	"go/types"
+	"strings"
+)
+
+// docMarkdown renders doc comment as Markdown. Headings in doc comment start from given level,
+// code blocks are fenced as Go code and doc links (e.g. [Name] and [pkg.Name]) point to
+// the same places as links in declarations. With CollapseDeprecated, docs of deprecated symbols are collapsed.
+func docMarkdown(res *resolver) func(string, int) string {
+	return func(text string, level int) string {
+		if res == nil || res.pkg == nil {
+			return text
+		}
+		parsed := res.pkg.Parser().Parse(text)
+		printer := &comment.Printer{
+			HeadingLevel: level,
+			HeadingID:    func(*comment.Heading) string { return "" },
+			DocLinkURL:   res.docLinkURL,
+		}
+		blocks := []string{}
+		for _, block := range parsed.Content {
+			if code, ok := block.(*comment.Code); ok {
+				blocks = append(blocks, "```go\n"+code.Text+"```\n")
+				continue
+			}
+			markdown := string(printer.Markdown(&comment.Doc{Content: []comment.Block{block}}))
+			// backticks in doc comments are meant as code spans, so they are not escaped
+			blocks = append(blocks, strings.ReplaceAll(markdown, "\\`", "`"))
+		}
+		for _, def := range parsed.Links {
+			if !def.Used {
+				blocks = append(blocks, "["+def.Text+"]: "+def.URL+"\n")
+			}
+		}
+		if res.output.CollapseDeprecated {
+			return collapseDeprecated(strings.Join(blocks, "\n"), text)
+		}
+		return strings.Join(blocks, "\n")
+	}
+}
+
+// docLinkURL returns URL for doc link.
+// Links to other packages are resolved only through imports of current package.
+func (res *resolver) docLinkURL(link *comment.DocLink) string {
+	pkg := res.types
+	if link.ImportPath != "" && link.ImportPath != res.importPath {
+		pkg = nil
+		if res.types != nil {
+			for _, imported := range res.types.Imports() {
+				if imported.Path() == link.ImportPath {
+					pkg = imported
+				}
+			}
+		}
+	}
+	importPath := link.ImportPath
+	if importPath == "" {
+		importPath = res.importPath
+	}
+	switch {
+	case link.Name == "" && importPath == res.importPath:
+		return "#"
+	case link.Name == "" && res.inModule(importPath) && res.output.combinedFile != "":
+		return "#" + anchorPrefix(importPath)
+	case link.Name == "" && res.inModule(importPath):
+		return res.packageLink(importPath)
+	case link.Name == "":
+		return "https://pkg.go.dev/" + importPath
+	case pkg == nil:
+		if link.Recv != "" {
+			return "https://pkg.go.dev/" + importPath + "#" + link.Recv + "." + link.Name
+		}
+		return "https://pkg.go.dev/" + importPath + "#" + link.Name
+	}
+	if link.Recv == "" {
+		if obj := pkg.Scope().Lookup(link.Name); obj != nil {
+			return res.objectURL(obj)
+		}
+		return ""
+	}
+	recv := pkg.Scope().Lookup(link.Recv)
+	if recv == nil {
+		return ""
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(recv.Type()), true, pkg, link.Name)
+	if method, ok := obj.(*types.Func); ok {
+		return res.methodURL(method)
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"go/doc/comment"
+	"strings"
+	"testing"
+)
+
+// TestDocMarkdown verifies that headings, code blocks, lists and doc links in doc comments are rendered as Markdown.
+func TestDocMarkdown(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a is linked.\npackage a\n\n// Base is base.\ntype Base struct{}\n",
+		"doc.go": `// Package docs has doc comments.
+//
+// # Usage
+//
+// Call [New] with [io.Reader], [a.Base] or [Config.Load]:
+//
+//	cfg := docs.New()
+//	cfg.Load()
+//
+// Steps:
+//   - first
+//   - second
+package docs
+
+import (
+	"io"
+
+	"example.com/mod/a"
+)
+
+// Config has [Limit] and uses [a].
+type Config struct{}
+
+// Limit is limit for ` + "`go build`" + `.
+const Limit = 10
+
+// New creates [Config].
+func New() *Config { return nil }
+
+// Load loads.
+func (c *Config) Load(r io.Reader, b a.Base) {}
+`,
+	})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"## Overview\nPackage docs has doc comments.\n\n### Usage\n\n",
+		"Call [New](#func-new) with [io.Reader](https://pkg.go.dev/io#Reader), [a.Base](a/README.md#type-base) " +
+			"or [Config.Load](#func-c-config-load):\n",
+		"```go\ncfg := docs.New()\ncfg.Load()\n```\n",
+		"  - first\n  - second\n",
+		"Config has [Limit](#constants) and uses [a](a/README.md).\n",
+		"New creates [Config](#type-config).\n",
+		"Limit is limit for `go build`.\n",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
+
+// TestDocLinkURL verifies that doc links point to local anchors, other packages in module and pkg.go.dev.
+func TestDocLinkURL(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a is linked.\npackage a\n\n// Base is base.\ntype Base struct{}\n",
+		"links.go": `// Package links has doc links.
+package links
+
+import (
+	"io"
+
+	"example.com/mod/a"
+)
+
+// Config has [a.Base].
+type Config struct{ r io.Reader }
+
+// Limit is limit.
+const Limit = 10
+
+// New creates [Config].
+func New() *Config { return nil }
+
+// Load loads.
+func (c *Config) Load(b a.Base) {}
+`,
+	})
+	importPath, mod, err := getPackageName(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgInfo, err := getPackage(dir, mod, importPath, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := newResolver(OutputSettings{Directory: dir}, mod, importPath, pkgInfo.imports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.info, res.types, res.pkg = pkgInfo.info, pkgInfo.types, &pkgInfo.pkg
+	tests := []struct {
+		link     comment.DocLink
+		expected string
+	}{
+		{link: comment.DocLink{}, expected: "#"},
+		{link: comment.DocLink{Name: "New"}, expected: "#func-new"},
+		{link: comment.DocLink{Name: "Limit"}, expected: "#constants"},
+		{link: comment.DocLink{Recv: "Config", Name: "Load"}, expected: "#func-c-config-load"},
+		{link: comment.DocLink{Name: "Missing"}, expected: ""},
+		{link: comment.DocLink{ImportPath: "example.com/mod/a"}, expected: "a/README.md"},
+		{link: comment.DocLink{ImportPath: "example.com/mod/a", Name: "Base"}, expected: "a/README.md#type-base"},
+		{link: comment.DocLink{ImportPath: "io"}, expected: "https://pkg.go.dev/io"},
+		{link: comment.DocLink{ImportPath: "io", Name: "Reader"}, expected: "https://pkg.go.dev/io#Reader"},
+		{link: comment.DocLink{ImportPath: "net/http", Recv: "Client", Name: "Do"}, expected: "https://pkg.go.dev/net/http#Client.Do"},
+	}
+	for _, tt := range tests {
+		if received := res.docLinkURL(&tt.link); received != tt.expected {
+			t.Errorf("docLinkURL(%#v) returned %#v, expected %#v", tt.link, received, tt.expected)
+		}
+	}
+}
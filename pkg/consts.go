@@ -0,0 +1,288 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// getStringValues finds String() methods, which switch over receiver and return string literals, e.g.
+//
+//	func (c Color) String() string {
+//		switch c {
+//		case Red:
+//			return "red"
+//		}
+//		...
+//	}
+//
+// or slice name table with index table like code generated by stringer does.
+// Returns map from type name to constant name to string value.
+// Method bodies are needed, so this must be called before doc.New removes them.
+func getStringValues(astPkg *ast.Package, info *types.Info) map[string]map[string]string {
+	values := map[string]map[string]string{}
+	for _, file := range astPkg.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "String" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+				continue
+			}
+			recv := fn.Recv.List[0]
+			typeName, ok := recv.Type.(*ast.Ident)
+			if !ok || len(recv.Names) != 1 {
+				continue
+			}
+			for _, stmt := range fn.Body.List {
+				switchStmt, ok := stmt.(*ast.SwitchStmt)
+				if !ok || !isIdent(switchStmt.Tag, recv.Names[0].Name) {
+					continue
+				}
+				values[typeName.Name] = caseStrings(switchStmt)
+			}
+			if _, ok := values[typeName.Name]; !ok && info != nil {
+				if strs := tableStrings(astPkg, info, fn, typeName.Name); len(strs) > 0 {
+					values[typeName.Name] = strs
+				}
+			}
+		}
+	}
+	return values
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// caseStrings maps constants in case clauses into string literals, which those clauses return.
+func caseStrings(switchStmt *ast.SwitchStmt) map[string]string {
+	values := map[string]string{}
+	for _, stmt := range switchStmt.Body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok || len(clause.Body) != 1 {
+			continue
+		}
+		ret, ok := clause.Body[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		lit, ok := ret.Results[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		for _, expr := range clause.List {
+			if ident, ok := expr.(*ast.Ident); ok {
+				values[ident.Name] = lit.Value
+			}
+		}
+	}
+	return values
+}
+
+// tableStrings maps constants of type into strings, which are sliced from name table with index table:
+//
+//	const _Color_name = "RedGreenBlue"
+//	var _Color_index = [...]uint8{0, 3, 8, 12}
+//
+//	func (i Color) String() string {
+//		i -= 1
+//		...
+//		return _Color_name[_Color_index[i]:_Color_index[i+1]]
+//	}
+//
+// Optional offset (i -= 1) is subtracted from constant values before they are looked up.
+func tableStrings(astPkg *ast.Package, info *types.Info, fn *ast.FuncDecl, typeName string) map[string]string {
+	recv := fn.Recv.List[0].Names[0].Name
+	var names, index *ast.Ident
+	offset := int64(0)
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.SUB_ASSIGN || len(n.Lhs) != 1 || !isIdent(n.Lhs[0], recv) {
+				return true
+			}
+			if value := info.Types[n.Rhs[0]].Value; value != nil {
+				offset, _ = constant.Int64Val(constant.ToInt(value))
+			}
+		case *ast.SliceExpr:
+			low, ok := n.Low.(*ast.IndexExpr)
+			if !ok {
+				return true
+			}
+			x, ok1 := n.X.(*ast.Ident)
+			idx, ok2 := low.X.(*ast.Ident)
+			if ok1 && ok2 {
+				names, index = x, idx
+			}
+		}
+		return true
+	})
+	if names == nil || index == nil {
+		return nil
+	}
+	nameConst, ok := info.Uses[names].(*types.Const)
+	if !ok || nameConst.Val().Kind() != constant.String {
+		return nil
+	}
+	name := constant.StringVal(nameConst.Val())
+	offsets := indexValues(astPkg, info, info.Uses[index])
+	values := map[string]string{}
+	for ident, obj := range info.Defs {
+		constObj, ok := obj.(*types.Const)
+		if !ok || constObj.Parent() != constObj.Pkg().Scope() {
+			continue
+		}
+		named, ok := constObj.Type().(*types.Named)
+		if !ok || named.Obj().Name() != typeName || named.Obj().Pkg() != constObj.Pkg() {
+			continue
+		}
+		value, exact := constant.Int64Val(constant.ToInt(constObj.Val()))
+		if idx := value - offset; exact && idx >= 0 && idx+1 < int64(len(offsets)) {
+			low, high := offsets[idx], offsets[idx+1]
+			if low <= high && high <= int64(len(name)) {
+				values[ident.Name] = strconv.Quote(name[low:high])
+			}
+		}
+	}
+	return values
+}
+
+// indexValues returns integer elements from composite literal, which initializes variable.
+func indexValues(astPkg *ast.Package, info *types.Info, obj types.Object) []int64 {
+	for _, file := range astPkg.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for idx, name := range valueSpec.Names {
+					if info.Defs[name] != obj || idx >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[idx].(*ast.CompositeLit)
+					if !ok {
+						return nil
+					}
+					values := []int64{}
+					for _, elt := range lit.Elts {
+						tv := info.Types[elt]
+						if tv.Value == nil {
+							return nil
+						}
+						value, exact := constant.Int64Val(constant.ToInt(tv.Value))
+						if !exact {
+							return nil
+						}
+						values = append(values, value)
+					}
+					return values
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// constValue returns evaluated value of constant, e.g. value of `1 << iota`.
+// Without type information, value is shown as it's written in source.
+func (res *resolver) constValue(name *ast.Ident, expr ast.Expr) string {
+	if res != nil && res.info != nil {
+		if obj, ok := res.info.Defs[name].(*types.Const); ok {
+			value := obj.Val()
+			switch value.Kind() {
+			case constant.Float, constant.Complex:
+				return value.String()
+			case constant.Unknown:
+				return ""
+			}
+			return value.ExactString()
+		}
+	}
+	if expr == nil {
+		return ""
+	}
+	return variableType(expr, 0, false, res).plainText
+}
+
+// constTable returns Markdown table of constants in group with their evaluated values and docs.
+// If type has String() method, which could be parsed with getStringValues, its values are shown too.
+// Otherwise, it's mentioned that type implements fmt.Stringer.
+func constTable(res *resolver, stringValues map[string]map[string]string) func(doc.Value, doc.Type) string {
+	return func(constObj doc.Value, typeObj doc.Type) string {
+		strs, hasString := stringValues[typeObj.Name]
+		rows := []string{}
+		for _, spec := range constObj.Decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			docText := docCell(res, valueSpec.Doc.Text()+"\n"+valueSpec.Comment.Text())
+			for idx, name := range valueSpec.Names {
+				if name.Name == "_" {
+					continue
+				}
+				var expr ast.Expr
+				if idx < len(valueSpec.Values) {
+					expr = valueSpec.Values[idx]
+				}
+				constName := name.Name
+				if valueSpecDeprecated(constObj.Decl, valueSpec) {
+					constName = "~~" + constName + "~~"
+				}
+				cells := []string{constName, codeCell(res.constValue(name, expr))}
+				if hasString {
+					cells = append(cells, codeCell(strs[name.Name]))
+				}
+				rows = append(rows, fmt.Sprintf("| %s | %s |", strings.Join(cells, " | "), docText))
+			}
+		}
+		if len(rows) == 0 {
+			return ""
+		}
+		header := "| Constant | Value | Doc |\n|----------|-------|-----|\n"
+		if hasString {
+			header = "| Constant | Value | String() | Doc |\n|----------|-------|----------|-----|\n"
+		}
+		table := header + strings.Join(rows, "\n") + "\n"
+		if !hasString && res.isStringer(typeObj) {
+			table += "\n" + typeObj.Name + " implements [fmt.Stringer](https://pkg.go.dev/fmt#Stringer).\n"
+		}
+		return table
+	}
+}
+
+// isStringer checks if values of type have String() method, which returns string.
+// It's used, when values from String() couldn't be found with getStringValues.
+func (res *resolver) isStringer(typeObj doc.Type) bool {
+	obj := res.typeObject(typeObj)
+	if obj == nil {
+		return false
+	}
+	sel := types.NewMethodSet(obj.Type()).Lookup(obj.Pkg(), "String")
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Obj().Type().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
+
+var codeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "&#124;")
+
+// codeCell shows value as code within Markdown table.
+func codeCell(value string) string {
+	if value == "" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, "`") {
+		value = strconv.Quote(unquoted)
+	}
+	return "<code>" + codeEscaper.Replace(value) + "</code>"
+}
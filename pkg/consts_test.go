@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConstTable verifies that typed constants are listed under their type with values and String() names.
+func TestConstTable(t *testing.T) {
+	dir := writeModule(t, map[string]string{"enum.go": `// Package enum has enums.
+package enum
+
+// Color is color.
+type Color int
+
+// Colors.
+const (
+	// Red is red, see [Flag].
+	Red Color = iota
+	Green // Green is green.
+	Blue
+)
+
+// Flag is flag.
+type Flag uint
+
+// Flags.
+const (
+	FlagA Flag = 1 << iota
+	FlagB
+	FlagC
+)
+
+// Prefix is prefixed.
+type Prefix string
+
+// Prefixes.
+const (
+	Hello Prefix = "hello " + "world"
+	Pipe  Prefix = ` + "`a|b`" + `
+)
+
+// Default is default color.
+var Default Color = Red
+
+// String returns name.
+func (c Color) String() string {
+	switch c {
+	case Red:
+		return "red"
+	case Green, Blue:
+		return "other"
+	}
+	return "unknown"
+}
+`,
+		"season.go": `package enum
+
+import "strconv"
+
+// Season is generated with stringer.
+type Season int
+
+// Seasons.
+const (
+	Spring Season = iota + 1
+	Summer
+	Autumn
+)
+
+const _Season_name = "SpringSummerAutumn"
+
+var _Season_index = [...]uint8{0, 6, 12, 18}
+
+func (i Season) String() string {
+	i -= 1
+	if i < 0 || i >= Season(len(_Season_index)-1) {
+		return "Season(" + strconv.FormatInt(int64(i+1), 10) + ")"
+	}
+	return _Season_name[_Season_index[i]:_Season_index[i+1]]
+}
+
+// Level has String() without known values.
+type Level int
+
+// Levels.
+const Low Level = 0
+
+func (l Level) String() string { return strconv.Itoa(int(l)) }
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		`Colors.
+
+| Constant | Value | String() | Doc |
+|----------|-------|----------|-----|
+| Red | <code>0</code> | <code>"red"</code> | Red is red, see [Flag](#type-flag). |
+| Green | <code>1</code> | <code>"other"</code> | Green is green. |
+| Blue | <code>2</code> | <code>"other"</code> |  |
+`,
+		"| FlagC | <code>4</code> |  |\n",
+		`| Hello | <code>"hello world"</code> |  |`,
+		`| Pipe | <code>"a&#124;b"</code> |  |`,
+		"<pre>\nvar Default Color = Red\n</pre>\nDefault is default color.\n",
+		"| Spring | <code>1</code> | <code>\"Spring\"</code> |  |\n| Summer | <code>2</code> | <code>\"Summer\"</code> |  |\n" +
+			"| Autumn | <code>3</code> | <code>\"Autumn\"</code> |  |\n",
+		"| Low | <code>0</code> |  |\n\nLevel implements [fmt.Stringer](https://pkg.go.dev/fmt#Stringer).\n",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
+
+// TestCodeCell verifies that values are escaped for Markdown table and raw strings are quoted.
+func TestCodeCell(t *testing.T) {
+	tests := []struct {
+		value, expected string
+	}{
+		{value: "", expected: ""},
+		{value: "10", expected: "<code>10</code>"},
+		{value: `"a|b"`, expected: `<code>"a&#124;b"</code>`},
+		{value: "`raw\\n`", expected: `<code>"raw\\n"</code>`},
+		{value: "1 << 2", expected: "<code>1 &lt;&lt; 2</code>"},
+		{value: `"<&>"`, expected: `<code>"&lt;&amp;&gt;"</code>`},
+	}
+	for _, tt := range tests {
+		if received := codeCell(tt.value); received != tt.expected {
+			t.Errorf("codeCell(%#v) returned %#v, expected %#v", tt.value, received, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"strings"
+)
+
+const deprecatedPrefix = "Deprecated: "
+
+// deprecation finds paragraph, which starts with "Deprecated: ", from doc comment.
+// Returns text after the prefix in single line (usually the replacement to use)
+// and whether symbol is deprecated at all.
+func deprecation(text string) (string, bool) {
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if strings.HasPrefix(paragraph, deprecatedPrefix) {
+			return strings.Join(strings.Fields(strings.TrimPrefix(paragraph, deprecatedPrefix)), " "), true
+		}
+	}
+	return "", false
+}
+
+// strikeDeprecated strikes through text, if doc comment has deprecation paragraph.
+func strikeDeprecated(text, docText string) string {
+	if _, ok := deprecation(docText); ok {
+		return "~~" + text + "~~"
+	}
+	return text
+}
+
+// collapseDeprecated hides rendered doc comment of deprecated symbol into <details> element,
+// which shows only the deprecation paragraph.
+func collapseDeprecated(markdown, docText string) string {
+	replacement, ok := deprecation(docText)
+	if !ok {
+		return markdown
+	}
+	summary := strings.TrimSpace(deprecatedPrefix + codeEscaper.Replace(replacement))
+	return fmt.Sprintf("<details><summary>%s</summary>\n\n%s\n</details>\n", summary, strings.TrimRight(markdown, "\n"))
+}
+
+// fieldDoc returns doc comment of struct field, both from above the field and from its line.
+func fieldDoc(field *ast.Field) string {
+	return strings.TrimSpace(field.Doc.Text() + "\n" + field.Comment.Text())
+}
+
+// deprecatedIndex lists deprecated package, types, functions, methods, fields, constants and
+// variables with links to them and the text from their deprecation paragraphs.
+// Doc links in deprecation paragraphs are resolved like in doc comments.
+func deprecatedIndex(res *resolver) func(doc.Package) []string {
+	render := docMarkdown(res)
+	return func(pkg doc.Package) []string {
+		return deprecatedItems(pkg, res, func(text string) string {
+			return strings.TrimSpace(render(text, 0))
+		})
+	}
+}
+
+// deprecatedItems returns items for deprecatedIndex. Deprecation paragraphs are rendered with render.
+func deprecatedItems(pkg doc.Package, res *resolver, render func(string) string) []string {
+	items := []string{}
+	add := func(name, anchor, docText string) {
+		replacement, ok := deprecation(docText)
+		if !ok {
+			return
+		}
+		item := fmt.Sprintf("[%s](#%s)", name, anchor)
+		if replacement != "" {
+			item += ": " + render(replacement)
+		}
+		items = append(items, item)
+	}
+	add("package "+pkg.Name, "overview", pkg.Doc)
+	addValues := func(kind, anchor string, values []*doc.Value) {
+		for _, value := range values {
+			for _, spec := range value.Decl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				docText := valueSpec.Doc.Text() + "\n" + valueSpec.Comment.Text()
+				if _, ok := deprecation(docText); !ok {
+					docText = value.Doc
+				}
+				for _, name := range valueSpec.Names {
+					if name.Name != "_" {
+						add(kind+" "+name.Name, anchor, docText)
+					}
+				}
+			}
+		}
+	}
+	addFuncs := func(funcs []*doc.Func) {
+		for _, funcObj := range funcs {
+			name := "func " + funcObj.Name
+			if funcObj.Recv != "" {
+				name = "method " + strings.TrimPrefix(funcObj.Recv, "*") + "." + funcObj.Name
+			}
+			add(name, res.funcAnchor(*funcObj), funcObj.Doc)
+		}
+	}
+	addValues("const", "constants", pkg.Consts)
+	addValues("var", "variables", pkg.Vars)
+	addFuncs(pkg.Funcs)
+	for _, typeObj := range pkg.Types {
+		anchor := res.typeAnchor(typeObj.Name)
+		add("type "+typeObj.Name, anchor, typeObj.Doc)
+		if spec := typeSpec(*typeObj); spec != nil {
+			if structType, ok := spec.Type.(*ast.StructType); ok {
+				for _, field := range structType.Fields.List {
+					for _, name := range field.Names {
+						add("field "+typeObj.Name+"."+name.Name, anchor, fieldDoc(field))
+					}
+				}
+			}
+		}
+		addValues("const", anchor, typeObj.Consts)
+		addValues("var", anchor, typeObj.Vars)
+		addFuncs(typeObj.Funcs)
+		addFuncs(typeObj.Methods)
+	}
+	return items
+}
+
+// valueSpecDeprecated checks if single constant or variable in group is deprecated.
+// Whole group is deprecated, when doc comment of declaration has deprecation paragraph.
+func valueSpecDeprecated(decl *ast.GenDecl, spec *ast.ValueSpec) bool {
+	for _, docText := range []string{decl.Doc.Text(), spec.Doc.Text() + "\n" + spec.Comment.Text()} {
+		if _, ok := deprecation(docText); ok {
+			return true
+		}
+	}
+	return false
+}
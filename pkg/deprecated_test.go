@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDeprecated verifies that deprecated symbols are struck through, collapsed and listed in Deprecated section.
+func TestDeprecated(t *testing.T) {
+	dir := writeModule(t, map[string]string{"dep.go": `// Package dep has deprecated symbols.
+package dep
+
+// Old does it.
+//
+// Deprecated: Use [New] instead.
+func Old() {}
+
+// New does it.
+func New() {}
+
+// Config is configuration.
+type Config struct {
+	Name string
+	// Deprecated: Use Name.
+	Title string
+}
+
+// Legacy is legacy.
+//
+// Deprecated: No replacement.
+type Legacy int
+
+const (
+	// Deprecated: Use LegacyB.
+	LegacyA Legacy = iota
+	LegacyB
+)
+
+// Close closes.
+//
+// Deprecated: Not needed
+// anymore.
+func (c *Config) Close() {}
+`})
+	for _, collapse := range []bool{false, true} {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, FieldTable: true, CollapseDeprecated: collapse}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		received := wc.String()
+		for _, expected := range []string{
+			"- ~~[func Old()](#func-old)~~ _(deprecated)_\n",
+			"- ~~[type Legacy](#type-legacy)~~ _(deprecated)_\n",
+			"    - ~~[func (c *Config) Close()](#func-c-config-close)~~ _(deprecated)_\n",
+			"## Deprecated\n\n- [func Old](#func-old): Use [New](#func-new) instead.\n" +
+				"- [field Config.Title](#type-config): Use Name.\n" +
+				"- [method Config.Close](#func-c-config-close): Not needed anymore.\n" +
+				"- [type Legacy](#type-legacy): No replacement.\n" +
+				"- [const LegacyA](#type-legacy): Use LegacyB.\n\n## Examples",
+			"### ~~func [Old](./dep.go#L7)~~\n",
+			"### ~~type [Legacy](./dep.go#L22)~~\n",
+			"| ~~Title~~ | string |",
+			"| ~~LegacyA~~ | <code>0</code> |",
+			"| LegacyB | <code>1</code> |",
+		} {
+			if !strings.Contains(received, expected) {
+				t.Errorf("CollapseDeprecated=%v: %#v is missing from %s", collapse, expected, received)
+			}
+		}
+		collapsed := "<details><summary>Deprecated: Use [New] instead.</summary>\n\nOld does it.\n\nDeprecated: Use [New](#func-new) instead.\n</details>\n"
+		if strings.Contains(received, collapsed) != collapse {
+			t.Errorf("CollapseDeprecated=%v: %#v in %s", collapse, collapsed, received)
+		}
+	}
+}
+
+// TestDeprecation verifies that deprecation paragraph is found only from beginning of paragraph.
+func TestDeprecation(t *testing.T) {
+	tests := []struct {
+		text, expected string
+		deprecated     bool
+	}{
+		{text: "", expected: "", deprecated: false},
+		{text: "Old does it.", expected: "", deprecated: false},
+		{text: "Old mentions Deprecated: inline.", expected: "", deprecated: false},
+		{text: "Old does it.\n\nDeprecated: Use New.\n", expected: "Use New.", deprecated: true},
+		{text: "Deprecated: Not needed\nanymore.\n\nOld does it.", expected: "Not needed anymore.", deprecated: true},
+	}
+	for _, tt := range tests {
+		received, deprecated := deprecation(tt.text)
+		if received != tt.expected || deprecated != tt.deprecated {
+			t.Errorf("deprecation(%#v) returned %#v, %v", tt.text, received, deprecated)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is number of unchanged lines around changes in unified diff
+const diffContext = 3
+
+// diffOp is one line in diff. Kind is ' ' for unchanged, '-' for removed and '+' for added line.
+// before and after are line indexes in original and new content.
+type diffOp struct {
+	kind   byte
+	text   string
+	before int
+	after  int
+}
+
+// splitLines splits text into lines, but keeps newlines, so that missing newline at
+// the end of file will also show up in diff.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines finds longest common subsequence between before and after and
+// returns list of operations, which turn before into after.
+// Common prefix and suffix are trimmed before building the LCS table, so that its size
+// depends only on the changed part of the content.
+func diffLines(before, after []string) []diffOp {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	ops := []diffOp{}
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: ' ', text: before[i], before: i, after: i})
+	}
+	ops = append(ops, diffMiddle(before[:len(before)-suffix], after[:len(after)-suffix], prefix)...)
+	for k := suffix; k > 0; k-- {
+		i, j := len(before)-k, len(after)-k
+		ops = append(ops, diffOp{kind: ' ', text: before[i], before: i, after: j})
+	}
+	return ops
+}
+
+// diffMiddle returns operations, which turn before[start:] into after[start:].
+func diffMiddle(before, after []string, start int) []diffOp {
+	// lcs[i][j] is length of longest common subsequence in before[start+i:] and after[start+j:]
+	rows, cols := len(before)-start, len(after)-start
+	lcs := make([][]int, rows+1)
+	for i := range lcs {
+		lcs[i] = make([]int, cols+1)
+	}
+	for i := rows - 1; i >= 0; i-- {
+		for j := cols - 1; j >= 0; j-- {
+			switch {
+			case before[start+i] == after[start+j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	ops := []diffOp{}
+	i, j := 0, 0
+	for i < rows || j < cols {
+		bi, aj := start+i, start+j
+		switch {
+		case i < rows && j < cols && before[bi] == after[aj]:
+			ops = append(ops, diffOp{kind: ' ', text: before[bi], before: bi, after: aj})
+			i++
+			j++
+		case j == cols || (i < rows && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', text: before[bi], before: bi, after: aj})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', text: after[aj], before: bi, after: aj})
+			j++
+		}
+	}
+	return ops
+}
+
+// hunkRange formats start and length of hunk in unified diff header.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// unifiedDiff returns changes between before and after in unified diff format.
+// Returns empty string, if there are no changes.
+func unifiedDiff(fname, before, after string) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+	sb := strings.Builder{}
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// extend hunk as long as there are at most 2*diffContext unchanged lines between changes
+		last := start
+		for end := start; end < len(ops) && end-last <= 2*diffContext; end++ {
+			if ops[end].kind != ' ' {
+				last = end
+			}
+		}
+		first := max(start-diffContext, 0)
+		stop := min(last+diffContext+1, len(ops))
+		beforeLen, afterLen := 0, 0
+		lines := []string{}
+		for _, op := range ops[first:stop] {
+			if op.kind != '+' {
+				beforeLen++
+			}
+			if op.kind != '-' {
+				afterLen++
+			}
+			line := string(op.kind) + op.text
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n\\ No newline at end of file\n"
+			}
+			lines = append(lines, line)
+		}
+		if sb.Len() == 0 {
+			sb.WriteString(fmt.Sprintf("--- %s\n+++ %s (generated)\n", fname, fname))
+		}
+		sb.WriteString(fmt.Sprintf(
+			"@@ -%s +%s @@\n", hunkRange(ops[first].before, beforeLen), hunkRange(ops[first].after, afterLen),
+		))
+		sb.WriteString(strings.Join(lines, ""))
+		start = stop
+	}
+	return sb.String()
+}
@@ -0,0 +1,49 @@
+package pkg
+
+import "testing"
+
+// TestUnifiedDiff verifies hunk headers, context lines and missing newlines in unified diff.
+func TestUnifiedDiff(t *testing.T) {
+	numbers := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	tests := []struct {
+		name, before, after, expected string
+	}{
+		{name: "no changes", before: "a\nb\n", after: "a\nb\n", expected: ""},
+		{
+			name: "changed line", before: "a\nb\nc\n", after: "a\nx\nc\n",
+			expected: "--- f\n+++ f (generated)\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "empty before", before: "", after: "a\n",
+			expected: "--- f\n+++ f (generated)\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "missing newline", before: "a\n", after: "a",
+			expected: "--- f\n+++ f (generated)\n@@ -1,1 +1,1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		{
+			name: "separate hunks", before: numbers, after: "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			expected: "--- f\n+++ f (generated)\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		{
+			name: "repeated lines", before: "a\na\n", after: "a\na\na\n",
+			expected: "--- f\n+++ f (generated)\n@@ -1,2 +1,3 @@\n a\n a\n+a\n",
+		},
+		{
+			name: "changed line between common prefix and suffix", before: numbers, after: "1\n2\n3\n4\nx\n6\n7\n8\n9\n10\n",
+			expected: "--- f\n+++ f (generated)\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "one hunk", before: numbers, after: "1\n2\nx\n4\n5\n6\n7\ny\n9\n10\n",
+			expected: "--- f\n+++ f (generated)\n@@ -1,10 +1,10 @@\n 1\n 2\n-3\n+x\n 4\n 5\n 6\n 7\n-8\n+y\n 9\n 10\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if received := unifiedDiff("f", tt.before, tt.after); received != tt.expected {
+				t.Errorf("expected %#v, received %#v", tt.expected, received)
+			}
+		})
+	}
+}
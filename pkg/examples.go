@@ -0,0 +1,157 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var outputComment = regexp.MustCompile(`^\s*// ?(Unordered )?[Oo]utput:`)
+
+// getTestFiles parses "*_test.go" files from directory, which belong to package
+// with given name or to its external test package.
+// Files are filtered with build constraints like `go test` does it. Files, which can't be parsed,
+// are skipped with warning, because examples shouldn't prevent documenting the package.
+func getTestFiles(fset *token.FileSet, directory, name string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("getTestFiles failed: %w", err)
+	}
+	files := []*ast.File{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		fname := filepath.Join(directory, entry.Name())
+		if match, err := build.Default.MatchFile(directory, entry.Name()); err != nil || !match {
+			if err != nil {
+				slog.Warn("skipping test file", "fname", fname, "err", err)
+			}
+			continue
+		}
+		file, err := parser.ParseFile(fset, fname, nil, parser.ParseComments)
+		if err != nil {
+			slog.Warn("skipping test file", "fname", fname, "err", err)
+			continue
+		}
+		if file.Name.Name == name || file.Name.Name == name+"_test" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// packageFiles returns files of package in file name order.
+func packageFiles(astPkg *ast.Package) []*ast.File {
+	fnames := []string{}
+	for fname := range astPkg.Files {
+		fnames = append(fnames, fname)
+	}
+	slices.Sort(fnames)
+	files := []*ast.File{}
+	for _, fname := range fnames {
+		files = append(files, astPkg.Files[fname])
+	}
+	return files
+}
+
+// exampleTitle returns title for example, e.g. "Example (Suffix)".
+func exampleTitle(example *doc.Example) string {
+	if example.Suffix == "" {
+		return "Example"
+	}
+	return fmt.Sprintf("Example (%s)", example.Suffix)
+}
+
+// exampleCode returns source code of example. Braces around function body and
+// output comment are removed. Whole file examples are returned as they are.
+func (res *resolver) exampleCode(example *doc.Example) string {
+	fset := token.NewFileSet()
+	if res != nil && res.fset != nil {
+		fset = res.fset
+	}
+	text := bytes.Buffer{}
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	node := &printer.CommentedNode{Node: example.Code, Comments: example.Comments}
+	if err := config.Fprint(&text, fset, node); err != nil {
+		return ""
+	}
+	code := text.String()
+	if _, ok := example.Code.(*ast.BlockStmt); !ok {
+		return code
+	}
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(code, "{\n"), "}"), "\n")
+	body := []string{}
+	for _, line := range lines {
+		if outputComment.MatchString(line) {
+			break
+		}
+		body = append(body, strings.TrimPrefix(line, "\t"))
+	}
+	return strings.TrimSpace(strings.Join(body, "\n")) + "\n"
+}
+
+// exampleSection renders example with heading of given level, its doc, code and expected output.
+func exampleSection(res *resolver) func(*doc.Example, int) string {
+	render := docMarkdown(res)
+	return func(example *doc.Example, level int) string {
+		lines := []string{strings.Repeat("#", level) + " " + exampleTitle(example), ""}
+		if example.Doc != "" {
+			lines = append(lines, render(example.Doc, level+1))
+		}
+		lines = append(lines, "```go\n"+res.exampleCode(example)+"```")
+		switch {
+		case example.Output != "":
+			output := "Output:"
+			if example.Unordered {
+				output = "Unordered output:"
+			}
+			lines = append(lines, "", output, "", "```\n"+example.Output+"```")
+		case example.EmptyOutput:
+			lines = append(lines, "", "Output is empty.")
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+}
+
+// exampleIndex lists examples, which are attached to functions, types and methods, with links to them.
+func exampleIndex(res *resolver) func(doc.Package) []string {
+	return func(pkg doc.Package) []string {
+		return exampleItems(pkg, res)
+	}
+}
+
+// exampleItems returns items for exampleIndex.
+func exampleItems(pkg doc.Package, res *resolver) []string {
+	items := []string{}
+	add := func(name, anchor string, examples []*doc.Example) {
+		for _, example := range examples {
+			items = append(items, fmt.Sprintf("[%s](#%s): %s", name, anchor, exampleTitle(example)))
+		}
+	}
+	for _, funcObj := range pkg.Funcs {
+		add(funcObj.Name, res.funcAnchor(*funcObj), funcObj.Examples)
+	}
+	for _, typeObj := range pkg.Types {
+		add(typeObj.Name, res.typeAnchor(typeObj.Name), typeObj.Examples)
+		for _, funcObj := range append(slices.Clone(typeObj.Funcs), typeObj.Methods...) {
+			name := funcObj.Name
+			if funcObj.Recv != "" {
+				name = strings.TrimPrefix(funcObj.Recv, "*") + "." + name
+			}
+			add(name, res.funcAnchor(*funcObj), funcObj.Examples)
+		}
+	}
+	return items
+}
@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExamples verifies that examples from test files are attached to their symbols with code and output.
+func TestExamples(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"ex.go": "// Package ex has examples.\npackage ex\n\n// Greet greets.\nfunc Greet() string { return \"hi\" }\n\n" +
+			"// Counter counts.\ntype Counter int\n\n// Inc increments.\nfunc (c *Counter) Inc() {}\n",
+		"ex_test.go": `package ex
+
+import "fmt"
+
+// ExampleGreet shows greeting.
+func ExampleGreet() {
+	fmt.Println(Greet())
+	// Output: hi
+}
+
+func ExampleCounter_Inc_twice() {
+	var c Counter
+	c.Inc()
+	c.Inc()
+}
+`,
+		"external_test.go": `package ex_test
+
+import "fmt"
+
+func ExampleCounter() {
+	fmt.Println(1)
+	fmt.Println(2)
+	// Unordered output:
+	// 2
+	// 1
+}
+`,
+		"whole_test.go": `package ex_test
+
+import "fmt"
+
+type helper struct{}
+
+func Example() {
+	fmt.Println(helper{})
+}
+`,
+		"ignored_test.go": "//go:build ignore\n\npackage ex\n\nfunc ExampleGreet_ignored() {}\n",
+		"broken_test.go":  "package ex\n\nfunc ExampleGreet_broken() {\n",
+	})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"## Examples\n\n### Example\n\n```go\npackage ex_test\n\nimport \"fmt\"\n\ntype helper struct{}\n",
+		"- [Greet](#func-greet): Example\n- [Counter](#type-counter): Example\n" +
+			"- [Counter.Inc](#func-c-counter-inc): Example (twice)\n",
+		"#### Example\n\nExampleGreet shows greeting.\n\n```go\nfmt.Println(Greet())\n```\n\nOutput:\n\n```\nhi\n```\n",
+		"#### Example\n\n```go\nfmt.Println(1)\nfmt.Println(2)\n```\n\nUnordered output:\n\n```\n2\n1\n```\n",
+		"#### Example (twice)\n\n```go\nvar c Counter\nc.Inc()\nc.Inc()\n```\n",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+	// files excluded by build constraints or with syntax errors are skipped
+	for _, unexpected := range []string{"ignored", "broken"} {
+		if strings.Contains(received, unexpected) {
+			t.Errorf("%#v should be missing from %s", unexpected, received)
+		}
+	}
+}
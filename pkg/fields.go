@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"strconv"
+	"strings"
+)
+
+// tableCell makes text safe for single cell in Markdown table.
+func tableCell(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", `\|`)
+}
+
+// docCell renders doc comment as Markdown for single table cell.
+// Deprecated docs are not collapsed, because their rows are already struck through.
+func docCell(res *resolver, text string) string {
+	if res != nil && res.output.CollapseDeprecated {
+		expanded := *res
+		expanded.output.CollapseDeprecated = false
+		res = &expanded
+	}
+	return tableCell(docMarkdown(res)(text, 0))
+}
+
+// fieldTable returns Markdown table with name, type, tags and doc of each struct field.
+// Returns empty string, if FieldTable is not set or type isn't struct.
+func fieldTable(res *resolver) func(doc.Type) string {
+	return func(typeObj doc.Type) string {
+		if res == nil || !res.output.FieldTable {
+			return ""
+		}
+		rows := []string{}
+		for _, spec := range typeObj.Decl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != typeObj.Name {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			res := res.withTypeParams(typeSpec.TypeParams)
+			for _, field := range structType.Fields.List {
+				rows = append(rows, fieldRows(field, res)...)
+			}
+		}
+		if len(rows) == 0 {
+			return ""
+		}
+		return "| Field | Type | Tag | Doc |\n|-------|------|-----|-----|\n" + strings.Join(rows, "\n") + "\n"
+	}
+}
+
+// fieldRows returns table row for every name in field.
+// Embedded field is named after its type.
+func fieldRows(field *ast.Field, res *resolver) []string {
+	vto := variableType(field.Type, 0, false, res)
+	fieldType := tableCell(vto.markdown)
+	tag := ""
+	if field.Tag != nil {
+		if value, err := strconv.Unquote(field.Tag.Value); err == nil && value != "" {
+			tag = "`" + tableCell(value) + "`"
+		}
+	}
+	docText := docCell(res, fieldDoc(field))
+	if len(field.Names) == 0 {
+		name := strings.TrimPrefix(vto.plainText, "*")
+		if idx := strings.Index(name, "["); idx != -1 { // generic type
+			name = name[:idx]
+		}
+		name = name[strings.LastIndex(name, ".")+1:]
+		return []string{fmt.Sprintf("| %s _(embedded)_ | %s | %s | %s |", name, fieldType, tag, docText)}
+	}
+	rows := []string{}
+	for _, name := range field.Names {
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s |", strikeDeprecated(name.Name, fieldDoc(field)), fieldType, tag, docText))
+	}
+	return rows
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFieldTable verifies that struct fields are listed in table with their types, tags and docs.
+func TestFieldTable(t *testing.T) {
+	dir := writeModule(t, map[string]string{"fields.go": `// Package fields has struct fields.
+package fields
+
+import "io"
+
+// Base is embedded.
+type Base struct{}
+
+// Config configures.
+type Config struct {
+	*Base
+	io.Reader
+	// Name is name of config for [io.Reader].
+	Name string ` + "`json:\"name\" yaml:\"name\"`" + `
+	X, Y int // coordinates
+	hidden bool
+}
+
+// Load loads.
+func (c *Config) Load() {}
+`})
+	for _, fieldTable := range []bool{false, true} {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, FieldTable: fieldTable}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		received := wc.String()
+		expected := `Config configures.
+
+| Field | Type | Tag | Doc |
+|-------|------|-----|-----|
+| Base _(embedded)_ | *<a href="#type-base">Base</a> |  |  |
+| Reader _(embedded)_ | <a href="https://pkg.go.dev/io#Reader">io.Reader</a> |  |  |
+| Name | string | ` + "`json:\"name\" yaml:\"name\"`" + ` | Name is name of config for [io.Reader](https://pkg.go.dev/io#Reader). |
+| X | int |  | coordinates |
+| Y | int |  | coordinates |
+
+| Method set | Receiver | Promoted from |
+`
+		if strings.Contains(received, expected) != fieldTable {
+			t.Errorf("FieldTable=%v: %#v in %s", fieldTable, expected, received)
+		}
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"go/ast"
 	"go/doc"
+	"go/token"
+	"go/types"
 	"log/slog"
-	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"text/template"
+	"unicode"
 )
 
 var (
@@ -16,66 +19,159 @@ var (
 	exportedType, _ = regexp.Compile("^[A-Z]")
 )
 
-func templateFuncs(version string, imports map[string]string, lineNumbers map[string]lineNumber) template.FuncMap {
+func templateFuncs(
+	version string, res *resolver, lineNumbers map[string]LineNumber, stringValues map[string]map[string]string,
+) template.FuncMap {
 	return template.FuncMap{
-		"trim":        strings.TrimSpace,
-		"funcElem":    funcElem,
-		"funcHeading": funcHeading(lineNumbers),
-		"funcSection": funcSection(imports),
-		"typeElem":    typeElem,
-		"typeHeading": typeHeading(lineNumbers),
-		"typeSection": typeSection(imports),
-		"varElem":     varElem(imports),
-		"version":     func() string { return version },
+		"trim":             strings.TrimSpace,
+		"constTable":       constTable(res, stringValues),
+		"deprecatedIndex":  deprecatedIndex(res),
+		"docMarkdown":      docMarkdown(res),
+		"exampleIndex":     exampleIndex(res),
+		"exampleSection":   exampleSection(res),
+		"fieldTable":       fieldTable(res),
+		"funcElem":         funcElem(res),
+		"funcHeading":      funcHeading(lineNumbers, res),
+		"funcSection":      funcSection(res),
+		"interfaceMethods": interfaceMethods(res),
+		"methodSet":        methodSet(res),
+		"noteSection":      noteSection(res),
+		"typeElem":         typeElem(res),
+		"typeHeading":      typeHeading(lineNumbers, res),
+		"typeSection":      typeSection(res),
+		"varElem":          varElem(res),
+		"version":          func() string { return version },
 	}
 }
 
+// intoLink turns text into anchor the same way as GitHub does it for headings.
+// Spaces become hyphens and other characters than letters, digits, hyphens and underscores are dropped.
 func intoLink(text string) string {
-	link := strings.ToLower(text)
-	link = strings.ReplaceAll(link, " ", "-")
-	link = strings.ReplaceAll(link, "*", "")
-	link = strings.ReplaceAll(link, "(", "")
-	link = strings.ReplaceAll(link, ")", "")
-	return link
+	link := strings.Builder{}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			link.WriteRune(r)
+		case r == ' ':
+			link.WriteRune('-')
+		}
+	}
+	return link.String()
+}
+
+// funcLink returns anchor for function or method.
+func funcLink(funcObj doc.Func) string {
+	return intoLink(fmt.Sprintf("func %s%s", funcReceiver(funcObj), funcObj.Name))
+}
+
+// funcDecl identifies function or method (e.g. "func Builder.Grow"), even when it has the same anchor
+// as another declaration.
+func funcDecl(funcObj doc.Func) string {
+	if funcObj.Recv == "" {
+		return "func " + funcObj.Name
+	}
+	recv := strings.TrimPrefix(funcObj.Recv, "*")
+	if idx := strings.Index(recv, "["); idx != -1 { // generic type
+		recv = recv[:idx]
+	}
+	return "func " + recv + "." + funcObj.Name
+}
+
+// getAnchors gives unique anchor for every function, method and type in package.
+// Keys are from funcDecl and "type " + name. Headings, which have the same anchor (e.g. methods
+// Grow and grow with All), are numbered in document order like GitHub does it: "func-b-builder-grow"
+// and "func-b-builder-grow-1".
+func getAnchors(pkg *doc.Package) map[string]string {
+	anchors := map[string]string{}
+	seen := map[string]int{}
+	add := func(decl, anchor string) {
+		if count := seen[anchor]; count > 0 {
+			anchors[decl] = fmt.Sprintf("%s-%d", anchor, count)
+		} else {
+			anchors[decl] = anchor
+		}
+		seen[anchor]++
+	}
+	for _, funcObj := range pkg.Funcs {
+		add(funcDecl(*funcObj), funcLink(*funcObj))
+	}
+	for _, typeObj := range pkg.Types {
+		add("type "+typeObj.Name, intoLink("type "+typeObj.Name))
+		for _, funcObj := range append(slices.Clone(typeObj.Funcs), typeObj.Methods...) {
+			add(funcDecl(*funcObj), funcLink(*funcObj))
+		}
+	}
+	return anchors
+}
+
+// recvTypeParams returns type parameters from receiver of generic method (e.g. K in `(s *Set[K])`).
+func recvTypeParams(funcObj doc.Func) []*ast.Ident {
+	if funcObj.Decl.Recv == nil || len(funcObj.Decl.Recv.List) == 0 {
+		return nil
+	}
+	recvType := funcObj.Decl.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	idents := []*ast.Ident{}
+	switch t := recvType.(type) {
+	case *ast.IndexExpr:
+		if ident, ok := t.Index.(*ast.Ident); ok {
+			idents = append(idents, ident)
+		}
+	case *ast.IndexListExpr:
+		for _, index := range t.Indices {
+			if ident, ok := index.(*ast.Ident); ok {
+				idents = append(idents, ident)
+			}
+		}
+	}
+	return idents
+}
+
+// typeParams combines type parameters into string (e.g. "[K comparable, V any]").
+// Returns empty string for non-generic functions and types.
+func typeParams(fields *ast.FieldList, res *resolver) varTypeOutput {
+	if fields == nil || len(fields.List) == 0 {
+		return sprintf("")
+	}
+	return sprintf("[%s]", funcParams(fields, res))
 }
 
-func intoImportLink(text string, imports map[string]string) string {
-	if imports == nil {
+func intoImportLink(text string, res *resolver) string {
+	if res == nil || res.typeParams[text] {
 		return text
 	}
-	switch text {
-	case "bool", "byte", "char", "error", "float", "float32", "float64", "int", "int32", "int64", "string":
+	if types.Universe.Lookup(text) != nil {
 		return text
 	}
 	if !strings.Contains(text, ".") {
-		if exportedType.MatchString(text) {
+		if exportedType.MatchString(text) || res.output.All {
 			return fmt.Sprintf(`<a href="#%s">%s</a>`, intoLink("type "+text), text)
 		}
 		slog.Warn(fmt.Sprintf("Internal type: %s", text))
 		return text
 	}
 	fields := strings.SplitN(text, ".", 2)
-	if modPath, ok := imports[fields[0]]; ok {
-		modFields := strings.Split(modPath, "/")
-		if len(modFields) >= 3 {
-			mainPath := strings.Join(strings.Split(imports["main"], "/")[0:3], "/")
-			if strings.Join(modFields[0:3], "/") == mainPath {
-				relPath, err := filepath.Rel(imports["main"], modPath)
-				if err != nil {
-					panic(fmt.Errorf(
-						"Unable to establish relative path imports['main']=%#v, modPath=%s",
-						imports["main"], modPath,
-					))
-				}
-				return fmt.Sprintf(`<a href="%s/README.md#%s">%s</a>`, relPath, intoLink("type "+fields[1]), text)
-			}
+	if modPath, ok := res.imports[fields[0]]; ok {
+		if res.inModule(modPath) {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, res.anchorLink(modPath, intoLink("type "+fields[1])), text)
 		}
 		return fmt.Sprintf(`<a href="https://pkg.go.dev/%s#%s">%s</a>`, modPath, fields[1], text)
 	}
 	return fmt.Sprintf(`<a href="https://pkg.go.dev/%s#%s">%s</a>`, fields[0], fields[1], text)
 }
 
-func typeField(field *ast.Field, depth int, hyphen bool, imports map[string]string) varTypeOutput {
+// fieldNames returns comma separated names of field (e.g. "X, Y").
+func fieldNames(field *ast.Field) string {
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func typeField(field *ast.Field, depth int, hyphen bool, res *resolver) varTypeOutput {
 	prefix := ""
 	for i := 0; i <= depth; i++ {
 		prefix = prefix + basePrefix
@@ -85,42 +181,56 @@ func typeField(field *ast.Field, depth int, hyphen bool, imports map[string]stri
 	}
 	switch t := field.Type.(type) {
 	case *ast.FuncType:
-		fparams := funcParams(t.Params, imports)
-		freturns := funcReturns(t.Results, imports)
-		msg := fmt.Sprintf("%sfunc %s(%%s)%%s", prefix, field.Names[0])
+		fparams := funcParams(t.Params, res)
+		freturns := funcReturns(t.Results, res)
+		msg := fmt.Sprintf("%s%s func(%%s)%%s", prefix, fieldNames(field))
 		return sprintf(msg, fparams, freturns)
 	default:
-		vto := variableType(field.Type, depth, hyphen, imports)
+		vto := variableType(field.Type, depth, hyphen, res)
 		plainIdx := strings.LastIndex(vto.plainText, "\n}")
 		mdIdx := strings.LastIndex(vto.markdown, "\n}")
 		if plainIdx != -1 {
 			vto.plainText = vto.plainText[:plainIdx+1] + prefix + vto.plainText[plainIdx+1:]
 			vto.markdown = vto.markdown[:mdIdx+1] + prefix + vto.markdown[mdIdx+1:]
 		}
-		msg := fmt.Sprintf("%s%s %%s", prefix, field.Names[0])
+		if len(field.Names) == 0 { // embedded field
+			return sprintf(prefix+"%s", vto)
+		}
+		msg := fmt.Sprintf("%s%s %%s", prefix, fieldNames(field))
 		return sprintf(msg, vto)
 	}
 }
 
 func funcReceiver(funcObj doc.Func) string {
-	receiver := ""
-	if funcObj.Recv != "" {
-		recv := funcObj.Decl.Recv.List[0]
-		receiver = fmt.Sprintf("(%s %s) ", recv.Names[0], funcObj.Recv)
+	if funcObj.Recv == "" {
+		return ""
+	}
+	name := ""
+	if recv := funcObj.Decl.Recv.List[0]; len(recv.Names) > 0 {
+		name = recv.Names[0].Name
+	}
+	return receiverText(name, funcObj.Recv)
+}
+
+// receiverText returns receiver as it's written in method heading, e.g. "(c *Config) ".
+// Unnamed receiver is only its type, e.g. "(Color) ".
+func receiverText(name, recvType string) string {
+	if name == "" {
+		return fmt.Sprintf("(%s) ", recvType)
 	}
-	return receiver
+	return fmt.Sprintf("(%s %s) ", name, recvType)
 }
 
 // funcParams combines function parameters into string.
 // If you start from "funcObj doc.Func", you will get ast.Field from
 // "funcObj.Decl.Type.Params.List"
-func funcParams(fields *ast.FieldList, imports map[string]string) varTypeOutput {
+func funcParams(fields *ast.FieldList, res *resolver) varTypeOutput {
 	if fields == nil {
 		return sprintf("")
 	}
 	varTypes := []varTypeOutput{}
 	for _, paramList := range fields.List {
-		vto := variableType(paramList.Type, 0, false, imports)
+		vto := variableType(paramList.Type, 0, false, res)
 		if len(paramList.Names) == 0 {
 			varTypes = append(varTypes, vto)
 			continue
@@ -139,194 +249,216 @@ func funcParams(fields *ast.FieldList, imports map[string]string) varTypeOutput
 // funcReturns combines function return values into string.
 // If you start from "funcObj doc.Func", you will get ast.Field from
 // "funcObj.Decl.Type.Results"
-func funcReturns(fields *ast.FieldList, imports map[string]string) varTypeOutput {
+func funcReturns(fields *ast.FieldList, res *resolver) varTypeOutput {
 	switch {
-	case fields == nil:
+	case fields == nil || len(fields.List) == 0:
 		return sprintf("")
-	case len(fields.List) == 1:
-		vto := variableType(fields.List[0].Type, 0, false, imports)
+	case len(fields.List) == 1 && len(fields.List[0].Names) == 0:
+		vto := variableType(fields.List[0].Type, 0, false, res)
 		return sprintf(" %s", vto)
 	default:
-		varTypes := []varTypeOutput{}
-		for _, param := range fields.List {
-			varTypes = append(varTypes, variableType(param.Type, 0, false, imports))
-		}
-		return sprintf(" (%s)", join(varTypes, ", "))
+		return sprintf(" (%s)", funcParams(fields, res))
+	}
+}
+
+func funcElem(res *resolver) func(doc.Func) string {
+	return func(funcObj doc.Func) string {
+		text := signature(funcObj.Decl, nil, nil)
+		// brackets in link text would be mistaken for another link
+		text = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+		link := fmt.Sprintf("[%s](#%s)", text, res.funcAnchor(funcObj))
+		return "- " + deprecatedLink(link, funcObj.Doc) + unexportedMark(funcObj.Name)
 	}
 }
 
-func funcElem(funcObj doc.Func) string {
-	text := fmt.Sprintf(
-		"func %s%s(%s)%s", funcReceiver(funcObj), funcObj.Name,
-		funcParams(funcObj.Decl.Type.Params, nil).plainText,
-		funcReturns(funcObj.Decl.Type.Results, nil).plainText,
-	)
-	link := intoLink(fmt.Sprintf("func %s%s", funcReceiver(funcObj), funcObj.Name))
-	return fmt.Sprintf("- [%s](#%s)", text, link)
+// unexportedMark returns badge for unexported name in index. Such names are documented only with All.
+func unexportedMark(name string) string {
+	if token.IsExported(name) {
+		return ""
+	}
+	return " _(unexported)_"
+}
+
+// emphasizeUnexported emphasizes heading of unexported name.
+// Unlike badge, emphasis doesn't change anchor of heading. HTML is used, because
+// asterisk of pointer receiver would end Markdown emphasis.
+func emphasizeUnexported(heading, name string) string {
+	if token.IsExported(name) {
+		return heading
+	}
+	return "<em>" + heading + "</em>"
 }
 
-func funcHeading(lineNumbers map[string]lineNumber) func(doc.Func) string {
+// deprecatedLink strikes through link in index and adds badge after it, if symbol is deprecated.
+func deprecatedLink(link, docText string) string {
+	if _, ok := deprecation(docText); ok {
+		return "~~" + link + "~~ _(deprecated)_"
+	}
+	return link
+}
+
+func funcHeading(lineNumbers map[string]LineNumber, res *resolver) func(doc.Func) string {
 	return func(funcObj doc.Func) string {
 		recv := funcReceiver(funcObj)
-		key := intoLink(fmt.Sprintf("func %s%s", recv, funcObj.Name))
+		key := res.funcAnchor(funcObj)
 		if value, ok := lineNumbers[key]; ok {
-			return fmt.Sprintf("func %s[%s](./%s#L%d)", recv, funcObj.Name, value.filename, value.line)
+			heading := emphasizeUnexported(fmt.Sprintf("func %s[%s](%s)", recv, funcObj.Name, res.sourceLink(value)), funcObj.Name)
+			return strikeDeprecated(heading, funcObj.Doc)
 		}
 		slog.Error(
 			"Failed to find line number in funcHeading",
 			"key", key, "lineNumbers", fmt.Sprintf("%#v", lineNumbers),
 		)
-		return fmt.Sprintf("func %s%s", recv, funcObj.Name)
+		return strikeDeprecated(emphasizeUnexported(fmt.Sprintf("func %s%s", recv, funcObj.Name), funcObj.Name), funcObj.Doc)
 	}
 }
 
-func funcSection(imports map[string]string) func(doc.Func) string {
+func funcSection(res *resolver) func(doc.Func) string {
 	return func(funcObj doc.Func) string {
-		return fmt.Sprintf(
-			"func %s%s(%s)%s", funcReceiver(funcObj), funcObj.Name,
-			funcParams(funcObj.Decl.Type.Params, imports).markdown,
-			funcReturns(funcObj.Decl.Type.Results, imports).markdown,
-		)
+		res := res.withTypeParams(funcObj.Decl.Type.TypeParams).withIdents(recvTypeParams(funcObj))
+		return linkSignature(signature(funcObj.Decl, res.fset, res.comments), funcObj.Decl, res)
 	}
 }
 
-func typeElem(typeObj doc.Type) string {
-	if len(typeObj.Decl.Specs) == 0 {
-		return ""
-	}
-	lines := []string{}
+// typeSpec returns type specification of typeObj from its declaration.
+// Specification is nil, if declaration doesn't have it.
+func typeSpec(typeObj doc.Type) *ast.TypeSpec {
 	for _, spec := range typeObj.Decl.Specs {
-		switch t := spec.(*ast.TypeSpec).Type.(type) {
-		case *ast.FuncType, *ast.Ident:
-			typeDesc := fmt.Sprintf("- type %s", typeObj.Name)
-			return typeDesc
-		case *ast.InterfaceType:
-			link := intoLink("type " + typeObj.Name)
-			typeDesc := fmt.Sprintf("- [type %s](#%s)", typeObj.Name, link)
-			return typeDesc
-		case *ast.StructType:
-			funcs := append(typeObj.Funcs, typeObj.Methods...)
-			for _, funcObj := range funcs {
-				lines = append(lines, "    "+funcElem(*funcObj))
-			}
-		default:
-			panic(fmt.Errorf(
-				"unknown parameter type %#v spec.(*ast.TypeSpec).Type=%#v",
-				t, spec.(*ast.TypeSpec).Type,
-			))
+		if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == typeObj.Name {
+			return typeSpec
 		}
 	}
-	fields := ""
-	if len(lines) > 0 {
-		fields = "\n" + strings.Join(lines, "\n")
+	return nil
+}
+
+func typeElem(res *resolver) func(doc.Type) string {
+	return func(typeObj doc.Type) string {
+		spec := typeSpec(typeObj)
+		if spec == nil {
+			return ""
+		}
+		alias := ""
+		if spec.Assign.IsValid() {
+			alias = " _(alias)_"
+		}
+		link := deprecatedLink(fmt.Sprintf("[type %s](#%s)", typeObj.Name, res.typeAnchor(typeObj.Name)), typeObj.Doc)
+		lines := []string{"- " + link + alias + unexportedMark(typeObj.Name)}
+		for _, funcObj := range append(slices.Clone(typeObj.Funcs), typeObj.Methods...) {
+			lines = append(lines, "    "+funcElem(res)(*funcObj))
+		}
+		return strings.Join(lines, "\n")
 	}
-	return fmt.Sprintf("- [type %s](#type-%s)%s", typeObj.Name, strings.ToLower(typeObj.Name), fields)
 }
 
-func typeHeading(lineNumbers map[string]lineNumber) func(string) string {
+func typeHeading(lineNumbers map[string]LineNumber, res *resolver) func(string) string {
 	return func(name string) string {
-		key := intoLink("type " + name)
+		docText := ""
+		if res != nil && res.pkg != nil {
+			for _, typeObj := range res.pkg.Types {
+				if typeObj.Name == name {
+					docText = typeObj.Doc
+				}
+			}
+		}
+		key := res.typeAnchor(name)
 		if value, ok := lineNumbers[key]; ok {
-			return fmt.Sprintf("type [%s](./%s#L%d)", name, value.filename, value.line)
+			return strikeDeprecated(emphasizeUnexported(fmt.Sprintf("type [%s](%s)", name, res.sourceLink(value)), name), docText)
 		}
 		slog.Error(
 			"Failed to find line number in typeHeading",
 			"key", key, "lineNumbers", fmt.Sprintf("%#v", lineNumbers),
 		)
-		return "type " + name
+		return strikeDeprecated(emphasizeUnexported("type "+name, name), docText)
 	}
 }
 
-func typeSection(imports map[string]string) func(doc.Type) string {
+func typeSection(res *resolver) func(doc.Type) string {
 	return func(typeObj doc.Type) string {
-		if len(typeObj.Decl.Specs) == 0 {
+		spec := typeSpec(typeObj)
+		if spec == nil {
 			return ""
 		}
+		res := res.withTypeParams(spec.TypeParams)
+		name := typeObj.Name + typeParams(spec.TypeParams, res).markdown
+		if spec.Assign.IsValid() {
+			name += " ="
+		}
 		lines := []string{}
-		typeName := ""
-		for _, spec := range typeObj.Decl.Specs {
-			switch t := spec.(*ast.TypeSpec).Type.(type) {
-			case *ast.FuncType, *ast.Ident:
-				typeDesc := fmt.Sprintf("<pre>\ntype %s %s\n</pre>\n", typeObj.Name, variableType(t, 0, false, imports).markdown)
-				return typeDesc
-			case *ast.InterfaceType:
-				typeName = "interface"
-				for _, field := range t.Methods.List {
-					lines = append(lines, typeField(field, 0, false, imports).markdown)
-				}
-				if len(t.Methods.List) > 0 {
-					lines = append(lines, "")
-				}
-			case *ast.StructType:
-				typeName = "struct"
-				maxLength := 0
-				structLines := []string{}
-				diffLen := []int{}
-				for _, field := range t.Fields.List {
-					info := typeField(field, 0, false, imports)
-					plainLen := len(strings.Split(info.plainText, "\n")[0])
-					mdLen := len(strings.Split(info.markdown, "\n")[0])
-					if plainLen > maxLength {
-						maxLength = plainLen
-					}
-					diffLen = append(diffLen, mdLen-plainLen)
-					structLines = append(structLines, info.markdown)
+		switch t := spec.Type.(type) {
+		case *ast.InterfaceType:
+			for _, field := range t.Methods.List {
+				if ft, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+					msg := fmt.Sprintf("%s%s(%%s)%%s", basePrefix, field.Names[0])
+					lines = append(lines, sprintf(msg, funcParams(ft.Params, res), funcReturns(ft.Results, res)).markdown)
+					continue
 				}
-				for idx, field := range t.Fields.List {
-					line := structLines[idx]
-					if field.Tag != nil {
-						msg := fmt.Sprintf("%%-%ds %%s", maxLength+diffLen[idx])
-						line = fmt.Sprintf(msg, line, field.Tag.Value)
-					}
-					lines = append(lines, line)
+				lines = append(lines, typeField(field, 0, false, res).markdown)
+			}
+			return fmt.Sprintf("type %s interface {%s}", name, blockLines(lines))
+		case *ast.StructType:
+			maxLength := 0
+			structLines := []string{}
+			diffLen := []int{}
+			for _, field := range t.Fields.List {
+				info := typeField(field, 0, false, res)
+				plainLen := len(strings.Split(info.plainText, "\n")[0])
+				mdLen := len(strings.Split(info.markdown, "\n")[0])
+				if plainLen > maxLength {
+					maxLength = plainLen
 				}
-				if len(t.Fields.List) > 0 {
-					lines = append(lines, "")
+				diffLen = append(diffLen, mdLen-plainLen)
+				structLines = append(structLines, info.markdown)
+			}
+			for idx, field := range t.Fields.List {
+				line := structLines[idx]
+				if field.Tag != nil {
+					msg := fmt.Sprintf("%%-%ds %%s", maxLength+diffLen[idx])
+					line = fmt.Sprintf(msg, line, field.Tag.Value)
 				}
-			default:
-				panic(fmt.Errorf(
-					"unknown parameter type %#v spec.(*ast.TypeSpec).Type=%#v",
-					t, spec.(*ast.TypeSpec).Type,
-				))
+				lines = append(lines, line)
 			}
+			return fmt.Sprintf("type %s struct {%s}", name, blockLines(lines))
+		default: // underlying type, e.g. map, slice, chan, pointer, func or other named type
+			return fmt.Sprintf("type %s %s", name, variableType(t, 0, false, res).markdown)
 		}
-		fields := ""
-		if len(lines) > 0 {
-			fields = "\n" + strings.Join(lines, "\n")
-		}
-		return fmt.Sprintf("type %s %s {%s}", typeObj.Name, typeName, fields)
 	}
 }
 
-func varElem(imports map[string]string) func(doc.Value, string) string {
+// blockLines joins lines of struct or interface body.
+func blockLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// preEscaper escapes plain text within <pre> element.
+var preEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// varElem returns declaration of constant or variable group, one line per spec.
+// Every name and value of spec is shown, e.g. "const C, D = 1, 2".
+func varElem(res *resolver) func(doc.Value, string) string {
 	return func(varObj doc.Value, varType string) string {
 		lines := []string{}
 		for _, spec := range varObj.Decl.Specs {
 			varItem := spec.(*ast.ValueSpec)
 			paramType := ""
 			if varItem.Type != nil {
-				paramType = " " + variableType(varItem.Type, 0, false, imports).plainText
+				paramType = " " + variableType(varItem.Type, 0, false, res).plainText
+			}
+			names := []string{}
+			for _, name := range varItem.Names {
+				names = append(names, name.Name)
 			}
 			paramName := ""
-			if len(varItem.Names) > 0 {
-				paramName = " " + varItem.Names[0].Name
+			if len(names) > 0 {
+				paramName = " " + strings.Join(names, ", ")
 			}
 			paramValue := ""
-			switch len(varItem.Values) {
-			case 0:
-			case 1:
-				value := variableType(varItem.Values[0], 0, false, imports).plainText
-				value = strings.Trim(value, paramType)
-				switch varItem.Values[0].(type) {
-				case *ast.ArrayType, *ast.MapType:
-					value = paramType + value
-					paramType = ""
-				}
-				paramValue = fmt.Sprintf(" = %s", value)
-			default:
+			if len(varItem.Values) > 0 {
 				values := []string{}
 				for _, value := range varItem.Values {
-					v := variableType(value, 0, false, imports).plainText
+					v := strings.TrimPrefix(variableType(value, 0, false, res).plainText, paramType)
 					switch value.(type) {
 					case *ast.ArrayType, *ast.MapType:
 						v = paramType + v
@@ -334,7 +466,7 @@ func varElem(imports map[string]string) func(doc.Value, string) string {
 					}
 					values = append(values, v)
 				}
-				paramValue = fmt.Sprintf(" {\n%s\n}", strings.Join(values, ", "))
+				paramValue = " = " + strings.Join(values, ", ")
 			}
 			paramComment := ""
 			if varItem.Comment != nil {
@@ -346,6 +478,6 @@ func varElem(imports map[string]string) func(doc.Value, string) string {
 			}
 			lines = append(lines, fmt.Sprintf("%s%s%s%s%s", varType, paramName, paramType, paramValue, paramComment))
 		}
-		return strings.Join(lines, "\n")
+		return preEscaper.Replace(strings.Join(lines, "\n"))
 	}
 }
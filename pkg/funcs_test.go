@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestUnnamedReceiver verifies that methods with unnamed receiver are documented.
+func TestUnnamedReceiver(t *testing.T) {
+	dir := writeModule(t, map[string]string{"color.go": `// Package color has methods without receiver names.
+package color
+
+// Color is color.
+type Color int
+
+// String returns name.
+func (Color) String() string { return "" }
+
+// Set sets color.
+func (*Color) Set(name string) {}
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"    - [func (*Color) Set(name string)](#func-color-set)\n",
+		"    - [func (Color) String() string](#func-color-string)\n",
+		"### func (*Color) [Set](./color.go#L11)\n",
+		"### func (Color) [String](./color.go#L8)\n",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
+
+// TestTypeDeclarations verifies that grouped, alias, composite and named type declarations are rendered.
+func TestTypeDeclarations(t *testing.T) {
+	dir := writeModule(t, map[string]string{"types.go": `// Package types has all kinds of types.
+package types
+
+import "io"
+
+// Types are grouped.
+type (
+	// Set is set of names.
+	Set map[string]struct{}
+	// Names is list.
+	Names []string
+	// Events is channel.
+	Events <-chan Set
+	// Ref is pointer.
+	Ref *Names
+	// Handler is function.
+	Handler func(name string) (ok bool)
+	// Reader is alias.
+	Reader = io.Reader
+	// Grid is array.
+	Grid [3][3]int
+	// Wrapper is named type.
+	Wrapper io.Writer
+)
+
+// NewSet creates set.
+func NewSet() Set { return nil }
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"- [type Reader](#type-reader) _(alias)_\n",
+		"- [type Set](#type-set)\n    - [func NewSet() Set](#func-newset)\n",
+		"<pre>\ntype Set map[string]struct{}\n</pre>\nSet is set of names.\n",
+		"<pre>\ntype Names []string\n</pre>\n",
+		`type Events <-chan <a href="#type-set">Set</a>`,
+		`type Ref *<a href="#type-names">Names</a>`,
+		"type Handler func(name string) (ok bool)\n",
+		`type Reader = <a href="https://pkg.go.dev/io#Reader">io.Reader</a>`,
+		"type Grid [3][3]int\n",
+		`type Wrapper <a href="https://pkg.go.dev/io#Writer">io.Writer</a>`,
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
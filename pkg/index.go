@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// IndexMode tells how internal and main packages are shown in module index.
+type IndexMode string
+
+const (
+	IndexInclude IndexMode = "include" // list package like any other package
+	IndexMark    IndexMode = "mark"    // list package with a badge
+	IndexExclude IndexMode = "exclude" // leave package out from index
+)
+
+var ErrUnknownIndexMode = errors.New("unknown index mode")
+
+// IndexData is given to "module-index" template, when module index is written.
+type IndexData struct {
+	ModulePath string       // module path from go.mod
+	Packages   []IndexEntry // packages in import path order
+}
+
+// IndexEntry describes one package in module index.
+type IndexEntry struct {
+	ImportPath string   // import path of the package
+	Name       string   // package name from package clause
+	Synopsis   string   // first sentence of package documentation
+	Link       string   // relative link to package documentation or empty, if it is not documented
+	Internal   bool     // package can only be imported within its parent directory tree
+	Main       bool     // package is a command
+	Badges     []string // marks (e.g. "internal" or "main") requested with IndexMode
+}
+
+// isInternal checks if import path has "internal" element in it.
+func isInternal(importPath string) bool {
+	return slices.Contains(strings.Split(importPath, "/"), "internal")
+}
+
+// packageClause reads package name and documentation from golang files in directory.
+func packageClause(dir string) (string, string, error) {
+	fset := token.NewFileSet()
+	astPackages, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return isProductionGo(fi.Name())
+	}, parser.ParseComments|parser.PackageClauseOnly)
+	if err != nil {
+		return "", "", err
+	}
+	if len(astPackages) != 1 {
+		return "", "", fmt.Errorf("%w %s", ErrNoPackageFound, dir)
+	}
+	for name, astPkg := range astPackages {
+		fnames := []string{}
+		for fname := range astPkg.Files {
+			fnames = append(fnames, fname)
+		}
+		slices.Sort(fnames)
+		for _, fname := range fnames {
+			if astPkg.Files[fname].Doc != nil && filepath.Base(fname) == "doc.go" {
+				return name, astPkg.Files[fname].Doc.Text(), nil
+			}
+		}
+		for _, fname := range fnames {
+			if astPkg.Files[fname].Doc != nil {
+				return name, astPkg.Files[fname].Doc.Text(), nil
+			}
+		}
+		return name, "", nil
+	}
+	return "", "", nil
+}
+
+// indexLink returns link from index file to documentation of package.
+// Returns empty string, if documentation is written only into Default writer.
+func (output *OutputSettings) indexLink(indexFile string, mod *module, importPath string) (string, error) {
+	var target string
+	var err error
+	switch {
+	case output.Combined:
+		target, err = output.combinedPath()
+	case output.Filename != "" || output.OutputDir != "":
+		target, err = output.outputPath(mod, importPath)
+	default:
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	link, err := filepath.Rel(filepath.Dir(indexFile), target)
+	if err != nil {
+		return "", fmt.Errorf("OutputSettings.indexLink failed: %w", err)
+	}
+	if output.Combined {
+		return filepath.ToSlash(link) + "#" + anchorPrefix(importPath), nil
+	}
+	return filepath.ToSlash(link), nil
+}
+
+// indexEntry creates IndexEntry for package in directory.
+// Returns nil, if package should be excluded from index.
+func (output *OutputSettings) indexEntry(indexFile, dir string, includeMain bool) (*IndexEntry, *module, error) {
+	importPath, mod, err := getPackageName(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	name, text, err := packageClause(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := &IndexEntry{
+		ImportPath: importPath,
+		Name:       name,
+		Synopsis:   strings.ReplaceAll((&doc.Package{}).Synopsis(text), "|", `\|`),
+		Internal:   isInternal(importPath),
+		Main:       name == "main",
+	}
+	for _, check := range []struct {
+		match bool
+		mode  IndexMode
+		badge string
+	}{
+		{match: entry.Internal, mode: output.IndexInternal, badge: "internal"},
+		{match: entry.Main, mode: output.IndexMain, badge: "main"},
+	} {
+		switch {
+		case !check.match:
+		case check.mode == IndexExclude:
+			return nil, mod, nil
+		case check.mode == IndexMark:
+			entry.Badges = append(entry.Badges, check.badge)
+		case check.mode != IndexInclude && check.mode != "":
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownIndexMode, check.mode)
+		}
+	}
+	if _, ok := deprecation(text); ok {
+		entry.Badges = append(entry.Badges, "deprecated")
+	}
+	if entry.Main && !includeMain {
+		entry.Badges = append(entry.Badges, "not documented")
+		return entry, mod, nil
+	}
+	if entry.Link, err = output.indexLink(indexFile, mod, importPath); err != nil {
+		return nil, nil, err
+	}
+	return entry, mod, nil
+}
+
+// writeIndex writes module index with every package from paths into Directory + Index.
+func writeIndex(out OutputSettings, version string, includeMain bool, paths []string) error {
+	indexFile, err := filepath.Abs(filepath.Join(out.Directory, out.Index))
+	if err != nil {
+		return fmt.Errorf("writeIndex failed: %w", err)
+	}
+	data := IndexData{}
+	for _, path := range paths {
+		entry, mod, err := out.indexEntry(indexFile, path, includeMain)
+		switch {
+		case errors.Is(err, ErrNoPackageFound):
+			slog.Warn("failed to find package from " + path)
+			continue
+		case err != nil:
+			return err
+		}
+		data.ModulePath = mod.path
+		if entry != nil {
+			data.Packages = append(data.Packages, *entry)
+		}
+	}
+	tmpl, err := out.parseTemplate(templateFuncs(version, nil, nil, nil))
+	if err != nil {
+		return fmt.Errorf("tmpl.Parse failed: %w", err)
+	}
+	content := bytes.Buffer{}
+	if err = tmpl.ExecuteTemplate(&content, "module-index", data); err != nil {
+		return fmt.Errorf("tmpl.Execute failed: %w", err)
+	}
+	out.Filename = out.Index
+	out.Markers = false
+	return out.save(content.Bytes())
+}
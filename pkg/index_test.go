@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestIndex verifies that module index lists packages according to IndexMode settings.
+func TestIndex(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go":          "// Package a is for testing. More details.\npackage a\n",
+		"internal/b/b.go": "// Package b is internal.\npackage b\n",
+		"cmd/c/main.go":   "// Command c is main.\npackage main\n",
+	})
+	out := OutputSettings{Directory: dir, Filename: "README.md", Index: "INDEX.md", IndexInternal: IndexMark}
+	t.Run("mark", func(t *testing.T) {
+		if err := RunDirTree(out, "test", false); err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(dir + "/INDEX.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{
+			"| [example.com/mod/a](a/README.md) | Package a is for testing. |\n",
+			"| example.com/mod/cmd/c `not documented` | Command c is main. |\n",
+			"| [example.com/mod/internal/b](internal/b/README.md) `internal` | Package b is internal. |\n",
+		} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("%#v is missing from %s", expected, string(content))
+			}
+		}
+	})
+	t.Run("exclude", func(t *testing.T) {
+		out.IndexInternal, out.IndexMain = IndexExclude, IndexExclude
+		if err := RunDirTree(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(dir + "/INDEX.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), "internal/b") || strings.Contains(string(content), "cmd/c") {
+			t.Errorf("excluded packages found from %s", string(content))
+		}
+	})
+	t.Run("unknown mode", func(t *testing.T) {
+		out.IndexMain = "hide"
+		if err := RunDirTree(out, "test", true); !errors.Is(err, ErrUnknownIndexMode) {
+			t.Errorf("RunDirTree should return ErrUnknownIndexMode instead of %v", err)
+		}
+	})
+}
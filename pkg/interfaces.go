@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/types"
+	"strings"
+)
+
+// interfaceMethods returns Markdown list of embedded interfaces and methods with their docs.
+// With ExpandInterfaces, methods of embedded interfaces are listed too.
+// Returns empty string, if type isn't interface or it has neither methods nor embedded interfaces.
+func interfaceMethods(res *resolver) func(doc.Type) string {
+	return func(typeObj doc.Type) string {
+		embedded := []string{}
+		entries := []string{}
+		for _, spec := range typeObj.Decl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != typeObj.Name {
+				continue
+			}
+			ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			res := res.withTypeParams(typeSpec.TypeParams)
+			for _, field := range ifaceType.Methods.List {
+				ft, isMethod := field.Type.(*ast.FuncType)
+				switch {
+				case isMethod && len(field.Names) > 0:
+					entries = append(entries, methodEntry(field, ft, res))
+				case isEmbeddedInterface(field.Type):
+					embedded = append(embedded, variableType(field.Type, 0, false, res).markdown)
+					if res != nil && res.output.ExpandInterfaces {
+						entries = append(entries, res.embeddedMethods(field.Type)...)
+					}
+				}
+			}
+		}
+		lines := []string{}
+		if len(embedded) > 0 {
+			lines = append(lines, "Embedded interfaces: "+strings.Join(embedded, ", "), "")
+		}
+		return strings.TrimSuffix(strings.Join(append(lines, entries...), "\n"), "\n")
+	}
+}
+
+// isEmbeddedInterface checks if expr names type (e.g. `io.Reader` or `Getter[T]`) instead of type set.
+func isEmbeddedInterface(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return true
+	case *ast.IndexExpr:
+		return isEmbeddedInterface(t.X)
+	case *ast.IndexListExpr:
+		return isEmbeddedInterface(t.X)
+	}
+	return false
+}
+
+// methodEntry returns list item with linked signature and doc (rendered as Markdown) of interface method.
+func methodEntry(field *ast.Field, ft *ast.FuncType, res *resolver) string {
+	decl := &ast.FuncDecl{Name: field.Names[0], Type: ft}
+	var sig string
+	if res == nil {
+		sig = signature(decl, nil, nil)
+	} else {
+		sig = linkSignature(signature(decl, res.fset, res.comments), decl, res)
+	}
+	entry := fmt.Sprintf("- <code>%s</code>", strings.TrimPrefix(sig, "func "))
+	docText := strings.TrimSpace(field.Doc.Text() + "\n" + field.Comment.Text())
+	if docText == "" {
+		return entry
+	}
+	docText = strings.TrimSpace(docMarkdown(res)(docText, 4))
+	return entry + "\n\n  " + strings.ReplaceAll(docText, "\n", "\n  ") + "\n"
+}
+
+// embeddedMethods returns list items for whole method set of embedded interface.
+// Methods are resolved with type information, so they don't have docs.
+func (res *resolver) embeddedMethods(expr ast.Expr) []string {
+	if res.info == nil {
+		return nil
+	}
+	tv, ok := res.info.Types[expr]
+	if !ok {
+		return nil
+	}
+	iface, ok := tv.Type.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	from := variableType(expr, 0, false, res)
+	qualifier := func(pkg *types.Package) string {
+		if pkg.Path() == res.importPath {
+			return ""
+		}
+		return pkg.Name()
+	}
+	entries := []string{}
+	for idx := 0; idx < iface.NumMethods(); idx++ {
+		method := iface.Method(idx)
+		sig := strings.TrimPrefix(types.TypeString(method.Type(), qualifier), "func")
+		entries = append(entries, fmt.Sprintf("- <code>%s%s</code> from %s", method.Name(), sig, from.markdown))
+	}
+	return entries
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestInterfaceMethods verifies that methods and embedded interfaces are listed under interface type.
+func TestInterfaceMethods(t *testing.T) {
+	dir := writeModule(t, map[string]string{"iface.go": `// Package iface has interfaces.
+package iface
+
+import "io"
+
+// Named has name.
+type Named interface {
+	// Name returns name.
+	Name() string
+}
+
+// ReadNamer reads with name.
+type ReadNamer interface {
+	io.Reader
+	Named
+	// Rename changes name of [Named].
+	// It returns old name.
+	Rename(name string) (old string, err error)
+	Close() error // Close closes.
+}
+`})
+	for _, expand := range []bool{false, true} {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, ExpandInterfaces: expand}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		received := wc.String()
+		for _, expected := range []string{
+			"- <code>Name() string</code>\n\n  Name returns name.\n",
+			`Embedded interfaces: <a href="https://pkg.go.dev/io#Reader">io.Reader</a>, <a href="#type-named">Named</a>`,
+			"- <code>Rename(name string) (old string, err error)</code>\n\n  Rename changes name of [Named](#type-named). It returns old name.\n",
+			"- <code>Close() error</code>\n\n  Close closes.\n",
+		} {
+			if !strings.Contains(received, expected) {
+				t.Errorf("%#v is missing from %s", expected, received)
+			}
+		}
+		expanded := "- <code>Read(p []byte) (n int, err error)</code> from <a href=\"https://pkg.go.dev/io#Reader\">io.Reader</a>"
+		if strings.Contains(received, expanded) != expand {
+			t.Errorf("ExpandInterfaces=%v: %#v in %s", expand, expanded, received)
+		}
+	}
+}
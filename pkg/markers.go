@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	MarkerBegin = "<!-- go2md:begin -->" // generated content starts after this line
+	MarkerEnd   = "<!-- go2md:end -->"   // generated content ends before this line
+)
+
+var ErrMarkersMissing = errors.New("markers are missing from")
+
+// markerWriter collects generated content and injects it between markers on Close.
+type markerWriter struct {
+	bytes.Buffer
+	fname string
+}
+
+// markerLine returns start and end (after newline) of first line from content[from:],
+// which has only marker in it. Returns -1 as start, if there is no such line.
+func markerLine(content []byte, marker string, from int) (int, int) {
+	for start := from; start < len(content); {
+		end := bytes.IndexByte(content[start:], '\n') + start + 1
+		if end == start {
+			end = len(content)
+		}
+		if string(bytes.TrimSpace(content[start:end])) == marker {
+			return start, end
+		}
+		start = end
+	}
+	return -1, -1
+}
+
+// injectMarkers replaces content between MarkerBegin and MarkerEnd in existing with generated.
+// Markers must be on lines by themselves, so that they can be mentioned in generated content.
+// Everything outside markers is kept as it is.
+// If existing is empty, returns generated content wrapped into markers.
+func injectMarkers(fname string, existing, generated []byte) ([]byte, error) {
+	if !bytes.HasSuffix(generated, []byte("\n")) {
+		generated = append(generated, '\n')
+	}
+	if len(existing) == 0 {
+		existing = []byte(MarkerBegin + "\n" + MarkerEnd + "\n")
+	}
+	begin, afterBegin := markerLine(existing, MarkerBegin, 0)
+	if begin == -1 {
+		return nil, fmt.Errorf("%w %s", ErrMarkersMissing, fname)
+	}
+	end, _ := markerLine(existing, MarkerEnd, afterBegin)
+	if end == -1 {
+		return nil, fmt.Errorf("%w %s", ErrMarkersMissing, fname)
+	}
+	content := bytes.Buffer{}
+	content.Write(existing[:afterBegin])
+	if !bytes.HasSuffix(existing[:afterBegin], []byte("\n")) {
+		content.WriteString("\n")
+	}
+	content.Write(generated)
+	content.Write(existing[end:])
+	return content.Bytes(), nil
+}
+
+// readExisting returns content of fname or nil, if file doesn't exist.
+func readExisting(fname string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Clean(fname))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (mw *markerWriter) Close() error {
+	existing, err := readExisting(mw.fname)
+	if err != nil {
+		return fmt.Errorf("markerWriter.Close failed: %w", err)
+	}
+	content, err := injectMarkers(mw.fname, existing, mw.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(mw.fname), content, 0o644)
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// TestMarkers verifies that only content between markers is replaced.
+func TestMarkers(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"doc.go": "// Package foo is for testing.\npackage foo\n",
+		"README.md": "# Intro\n\nHand-written text.\n\n" + MarkerBegin + "\nold docs\n" + MarkerEnd +
+			"\n\nHand-written outro.\n",
+		"MISSING.md": "# Intro\n",
+	})
+	var wc writeCloser
+	out := OutputSettings{
+		Default: &wc, Directory: dir, Filename: "README.md", Template: dir + "/custom.tmpl", Markers: true,
+	}
+	if err := os.WriteFile(out.Template, []byte("new docs for {{ .Name }}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("inject", func(t *testing.T) {
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(dir + "/README.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "# Intro\n\nHand-written text.\n\n" + MarkerBegin + "\nnew docs for foo\n" + MarkerEnd +
+			"\n\nHand-written outro.\n"
+		if string(content) != expected {
+			t.Errorf("expected %#v, received %#v", expected, string(content))
+		}
+		out.Check = true
+		if err = RunDirectory(out, "test", true); err != nil {
+			t.Errorf("check failed after inject: %v", err)
+		}
+		out.Check = false
+	})
+	t.Run("markers in generated content", func(t *testing.T) {
+		tmpl := "docs for {{ .Name }}\nconst MarkerBegin = \"" + MarkerBegin + "\"\nconst MarkerEnd = \"" + MarkerEnd + "\"\n"
+		if err := os.WriteFile(out.Template, []byte(tmpl), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = os.WriteFile(out.Template, []byte("new docs for {{ .Name }}\n"), 0o600) }()
+		for i := 0; i < 2; i++ {
+			if err := RunDirectory(out, "test", true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		content, err := os.ReadFile(dir + "/README.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "# Intro\n\nHand-written text.\n\n" + MarkerBegin + "\ndocs for foo\nconst MarkerBegin = \"" +
+			MarkerBegin + "\"\nconst MarkerEnd = \"" + MarkerEnd + "\"\n" + MarkerEnd + "\n\nHand-written outro.\n"
+		if string(content) != expected {
+			t.Errorf("expected %#v, received %#v", expected, string(content))
+		}
+		out.Check = true
+		if err = RunDirectory(out, "test", true); err != nil {
+			t.Errorf("check failed after repeated inject: %v", err)
+		}
+		out.Check = false
+	})
+	t.Run("markers missing", func(t *testing.T) {
+		out.Filename = "MISSING.md"
+		if err := RunDirectory(out, "test", true); !errors.Is(err, ErrMarkersMissing) {
+			t.Errorf("RunDirectory should return ErrMarkersMissing instead of %v", err)
+		}
+	})
+}
+
+// TestInjectMarkers verifies that generated content is wrapped into markers and misplaced markers are errors.
+func TestInjectMarkers(t *testing.T) {
+	tests := []struct {
+		name, existing, expected string
+		err                      error
+	}{
+		{name: "empty", existing: "", expected: MarkerBegin + "\nnew\n" + MarkerEnd + "\n"},
+		{
+			name: "replace", existing: "intro\n" + MarkerBegin + "\nold\n" + MarkerEnd + "\noutro\n",
+			expected: "intro\n" + MarkerBegin + "\nnew\n" + MarkerEnd + "\noutro\n",
+		},
+		{
+			name: "markers within lines", existing: "intro\n" + MarkerBegin + "\nold `" + MarkerEnd + "`\n" + MarkerEnd + "\noutro\n",
+			expected: "intro\n" + MarkerBegin + "\nnew\n" + MarkerEnd + "\noutro\n",
+		},
+		{name: "mentioned markers only", existing: "Use `" + MarkerBegin + "` and `" + MarkerEnd + "`.\n", err: ErrMarkersMissing},
+		{name: "missing markers", existing: "hand-written\n", err: ErrMarkersMissing},
+		{name: "markers in wrong order", existing: MarkerEnd + "\n" + MarkerBegin + "\n", err: ErrMarkersMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received, err := injectMarkers("README.md", []byte(tt.existing), []byte("new"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, received %v", tt.err, err)
+			}
+			if string(received) != tt.expected {
+				t.Errorf("expected %#v, received %#v", tt.expected, string(received))
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/types"
+	"strings"
+)
+
+// methodSet returns Markdown table of exported methods, which type has either by itself or
+// through embedded fields. Methods, which need pointer receiver, are marked as such.
+// Returns empty string for interfaces and types without methods.
+func methodSet(res *resolver) func(doc.Type) string {
+	return func(typeObj doc.Type) string {
+		obj := res.typeObject(typeObj)
+		if obj == nil || types.IsInterface(obj.Type()) {
+			return ""
+		}
+		valueSet := types.NewMethodSet(obj.Type())
+		pointerSet := types.NewMethodSet(types.NewPointer(obj.Type()))
+		rows := []string{}
+		for idx := 0; idx < pointerSet.Len(); idx++ {
+			sel := pointerSet.At(idx)
+			method, ok := sel.Obj().(*types.Func)
+			if !ok || !res.documented(method) {
+				continue
+			}
+			receiver := "pointer"
+			if valueSet.Lookup(method.Pkg(), method.Name()) != nil {
+				receiver = "value"
+			}
+			from := ""
+			if len(sel.Index()) > 1 { // promoted through embedded field
+				if named := receiverNamed(method); named != nil {
+					from = res.objectLink(named.Obj(), qualifiedName(named.Obj(), res.importPath))
+				}
+			}
+			rows = append(rows, fmt.Sprintf("| %s | %s | %s |", res.methodLink(method), receiver, from))
+		}
+		if len(rows) == 0 {
+			return ""
+		}
+		return "| Method set | Receiver | Promoted from |\n|------------|----------|---------------|\n" +
+			strings.Join(rows, "\n") + "\n"
+	}
+}
+
+// typeObject returns type checked object for type, if there is one.
+func (res *resolver) typeObject(typeObj doc.Type) *types.TypeName {
+	if res == nil || res.info == nil {
+		return nil
+	}
+	for _, spec := range typeObj.Decl.Specs {
+		if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == typeObj.Name {
+			obj, _ := res.info.Defs[typeSpec.Name].(*types.TypeName)
+			return obj
+		}
+	}
+	return nil
+}
+
+// receiverNamed returns named type, where method has been declared.
+func receiverNamed(method *types.Func) *types.Named {
+	sig, ok := method.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+	recvType := sig.Recv().Type()
+	if ptr, ok := recvType.(*types.Pointer); ok {
+		recvType = ptr.Elem()
+	}
+	named, _ := recvType.(*types.Named)
+	return named
+}
+
+// qualifiedName returns name of object with package name, if object is from other package.
+func qualifiedName(obj types.Object, importPath string) string {
+	if obj.Pkg() == nil || obj.Pkg().Path() == importPath {
+		return obj.Name()
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
+
+// methodLink returns method name with link to its documentation.
+func (res *resolver) methodLink(method *types.Func) string {
+	if url := res.methodURL(method); url != "" {
+		return fmt.Sprintf(`<a href="%s">%s</a>`, url, method.Name())
+	}
+	return method.Name()
+}
+
+// methodURL returns URL to documentation of method.
+// Methods of interfaces link to interface itself, since they don't have own headings.
+func (res *resolver) methodURL(method *types.Func) string {
+	named := receiverNamed(method)
+	if named == nil || method.Pkg() == nil {
+		return ""
+	}
+	typeName := named.Obj()
+	importPath := method.Pkg().Path()
+	if importPath != res.importPath && !res.inModule(importPath) {
+		return fmt.Sprintf("https://pkg.go.dev/%s#%s.%s", importPath, typeName.Name(), method.Name())
+	}
+	if !res.documented(typeName) {
+		return ""
+	}
+	anchor, decl := intoLink("type "+typeName.Name()), "type "+typeName.Name()
+	if !types.IsInterface(named) {
+		sig := method.Type().(*types.Signature)
+		recvType := types.TypeString(sig.Recv().Type(), func(*types.Package) string { return "" })
+		anchor = intoLink("func " + receiverText(sig.Recv().Name(), recvType) + method.Name())
+		decl = "func " + typeName.Name() + "." + method.Name()
+	}
+	if importPath == res.importPath {
+		return "#" + res.declAnchor(decl, anchor)
+	}
+	return res.anchorLink(importPath, anchor)
+}
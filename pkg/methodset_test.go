@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMethodSet verifies that method set lists own and promoted methods with their receiver kinds.
+func TestMethodSet(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a is embedded.\npackage a\n\n// Base is base.\ntype Base struct{}\n\n// ID returns id.\nfunc (b *Base) ID() int { return 0 }\n",
+		"ms.go": `// Package ms has method sets.
+package ms
+
+import (
+	"io"
+
+	"example.com/mod/a"
+)
+
+// Bar is embedded.
+type Bar struct{}
+
+// Hello says hello.
+func (b Bar) Hello() string { return "" }
+
+// Bye has unnamed receiver.
+func (Bar) Bye() {}
+
+// Foo embeds.
+type Foo struct {
+	Bar
+	*a.Base
+	io.Closer
+}
+
+// Own is own method.
+func (f *Foo) Own() {}
+`,
+	})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	expected := `Foo embeds.
+
+| Method set | Receiver | Promoted from |
+|------------|----------|---------------|
+| <a href="#func-bar-bye">Bye</a> | value | <a href="#type-bar">Bar</a> |
+| <a href="https://pkg.go.dev/io#Closer.Close">Close</a> | value | <a href="https://pkg.go.dev/io#Closer">io.Closer</a> |
+| <a href="#func-b-bar-hello">Hello</a> | value | <a href="#type-bar">Bar</a> |
+| <a href="a/README.md#func-b-base-id">ID</a> | value | <a href="a/README.md#type-base">a.Base</a> |
+| <a href="#func-f-foo-own">Own</a> | pointer |  |
+
+`
+	if !strings.Contains(received, expected) {
+		t.Errorf("%#v is missing from %s", expected, received)
+	}
+	// embedded fields have only type in declaration
+	declaration := `type Foo struct {
+    <a href="#type-bar">Bar</a>
+    *<a href="a/README.md#type-base">a.Base</a>
+    <a href="https://pkg.go.dev/io#Closer">io.Closer</a>
+}`
+	if !strings.Contains(received, declaration) {
+		t.Errorf("%#v is missing from %s", declaration, received)
+	}
+}
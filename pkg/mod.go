@@ -15,39 +15,57 @@ var (
 	ErrGoModMissing      = errors.New("unable to find go.mod")
 )
 
+// module has the information that we need from go.mod file.
+type module struct {
+	dir       string // directory, where go.mod is
+	path      string // module path from module directive
+	goVersion string // version from go directive
+
+	importer *sourceImporter // type checks imported packages, nil until forModule is called
+}
+
 func hasGoMod(dir string) bool {
 	_, err := os.Stat(dir + "/go.mod")
 	return !os.IsNotExist(err)
 }
 
-func moduleName(dir string) (string, error) {
+func readModule(dir string) (*module, error) {
 	f, err := os.Open(filepath.Clean(dir + "/go.mod"))
 	if err != nil {
-		err = fmt.Errorf("moduleName failed: %w", err)
+		err = fmt.Errorf("readModule failed: %w", err)
 		slog.Error(err.Error(), "dir", dir)
-		return "", err
+		return nil, err
 	}
 	defer f.Close()
+	mod := &module{dir: dir}
 	scan := bufio.NewScanner(f)
 	scan.Split(bufio.ScanLines)
 	for scan.Scan() {
 		line := scan.Text()
-		if strings.HasPrefix(line, "module ") {
+		switch {
+		case strings.HasPrefix(line, "module "):
 			words := strings.Split(line, " ")
 			if len(words) > 1 {
-				return words[1], nil
+				mod.path = words[1]
+				continue
 			}
 			slog.Error("module name missing from line", "line", line)
+		case strings.HasPrefix(line, "go "):
+			mod.goVersion = strings.TrimSpace(strings.TrimPrefix(line, "go "))
 		}
 	}
-	return "", fmt.Errorf("%w from %s/go.mod", ErrModuleNameMissing, dir)
+	if mod.path == "" {
+		return nil, fmt.Errorf("%w from %s/go.mod", ErrModuleNameMissing, dir)
+	}
+	return mod, nil
 }
 
 // getPackageName assumes that each directory has one package name in golang namespace.
-func getPackageName(dir string) (string, error) {
+// Returns also module, where package belongs to.
+func getPackageName(dir string) (string, *module, error) {
 	cwd, err := filepath.Abs(dir)
 	if err != nil {
-		return "", fmt.Errorf("getPackageName failed: %w", err)
+		return "", nil, fmt.Errorf("getPackageName failed: %w", err)
 	}
 	dirs := strings.Split(cwd, "/")
 	for idx := len(dirs); idx > 0; idx-- {
@@ -55,14 +73,14 @@ func getPackageName(dir string) (string, error) {
 		if !hasGoMod(root) {
 			continue
 		}
-		modName, err := moduleName(root)
+		mod, err := readModule(root)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
-		parts := append([]string{modName}, dirs[idx:]...)
-		return strings.Join(parts, "/"), nil
+		parts := append([]string{mod.path}, dirs[idx:]...)
+		return strings.Join(parts, "/"), mod, nil
 	}
-	return "", fmt.Errorf("%w from %s or its parent dirs", ErrGoModMissing, cwd)
+	return "", nil, fmt.Errorf("%w from %s or its parent dirs", ErrGoModMissing, cwd)
 }
 
 func fileExists(fname string) bool {
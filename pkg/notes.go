@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"fmt"
+	"go/doc"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultNoteMarkers are always rendered into Notes section.
+// go/doc collects every "MARKER(uid): body" comment, so other markers are only picked from
+// doc.Package.Notes, when they are listed in OutputSettings.NoteMarkers.
+var DefaultNoteMarkers = []string{"BUG"}
+
+// noteMarkers returns markers in order, in which they are rendered.
+func (output *OutputSettings) noteMarkers() []string {
+	markers := slices.Clone(DefaultNoteMarkers)
+	for _, marker := range output.NoteMarkers {
+		marker = strings.ToUpper(strings.TrimSpace(marker))
+		if marker != "" && !slices.Contains(markers, marker) {
+			markers = append(markers, marker)
+		}
+	}
+	return markers
+}
+
+// noteSection renders notes grouped by marker with their authors and links to source lines.
+// Headings of markers are on given level. Returns empty string, if there are no notes to render.
+func noteSection(res *resolver) func(map[string][]*doc.Note, int) string {
+	return func(notes map[string][]*doc.Note, level int) string {
+		if res == nil || res.fset == nil {
+			return ""
+		}
+		groups := []string{}
+		for _, marker := range res.output.noteMarkers() {
+			if len(notes[marker]) == 0 {
+				continue
+			}
+			lines := []string{strings.Repeat("#", level) + " " + marker, ""}
+			for _, note := range notes[marker] {
+				position := res.fset.Position(note.Pos)
+				lineNumber := LineNumber{Filename: filepath.Base(position.Filename), Line: position.Line}
+				lines = append(lines, fmt.Sprintf(
+					"- [%s:%d](%s) **%s**: %s", lineNumber.Filename, lineNumber.Line, res.sourceLink(lineNumber),
+					note.UID, strings.Join(strings.Fields(note.Body), " "),
+				))
+			}
+			groups = append(groups, strings.Join(lines, "\n")+"\n")
+		}
+		return strings.Join(groups, "\n")
+	}
+}
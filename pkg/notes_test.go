@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNotes verifies that BUG, TODO and other notes are collected with their authors.
+func TestNotes(t *testing.T) {
+	dir := writeModule(t, map[string]string{"notes.go": `// Package notes has notes.
+package notes
+
+// BUG(alice): Parse fails on
+// empty input.
+
+// TODO(bob): Cache results.
+
+// Parse parses.
+func Parse() {}
+
+// SECURITY(carol): Validate input.
+`})
+	for _, tc := range []struct {
+		markers  []string
+		expected string
+	}{
+		{
+			expected: "## Notes\n\n### BUG\n\n- [notes.go:4](./notes.go#L4) **alice**: Parse fails on empty input.\n\n\n--",
+		},
+		{
+			markers: []string{"security", "todo"},
+			expected: "## Notes\n\n### BUG\n\n- [notes.go:4](./notes.go#L4) **alice**: Parse fails on empty input.\n\n" +
+				"### SECURITY\n\n- [notes.go:12](./notes.go#L12) **carol**: Validate input.\n\n" +
+				"### TODO\n\n- [notes.go:7](./notes.go#L7) **bob**: Cache results.\n\n\n--",
+		},
+	} {
+		var wc writeCloser
+		if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir, NoteMarkers: tc.markers}, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		if received := wc.String(); !strings.Contains(received, tc.expected) {
+			t.Errorf("NoteMarkers=%v: %#v is missing from %s", tc.markers, tc.expected, received)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hasProductionGo checks if directory has any golang files, which are not only used for `go test`.
+func hasProductionGo(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && isProductionGo(entry.Name()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// outputFiles returns absolute paths to output files of all packages in Directory and its subdirectories.
+// Paths are resolved with outputPath, so they follow OutputDir and Filename template.
+// Module index and combined document are included too.
+func (output *OutputSettings) outputFiles() (map[string]bool, error) {
+	files := map[string]bool{}
+	err := filepath.WalkDir(output.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		hasGo, err := hasProductionGo(path)
+		if err != nil || !hasGo {
+			return err
+		}
+		importPath, mod, err := getPackageName(path)
+		if err != nil {
+			return err
+		}
+		fname, err := output.outputPath(mod, importPath)
+		if err != nil {
+			return err
+		}
+		files[fname] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	extra := []string{}
+	if output.Index != "" {
+		extra = append(extra, filepath.Join(output.Directory, output.Index))
+	}
+	if output.Combined {
+		combinedFile, err := output.combinedPath()
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, combinedFile)
+	}
+	for _, fname := range extra {
+		fname, err := filepath.Abs(fname)
+		if err != nil {
+			return nil, err
+		}
+		files[fname] = true
+	}
+	return files, nil
+}
+
+// isOutputName checks if file could be output file based on its name.
+// With Filename template, only file extension can be compared.
+func (output *OutputSettings) isOutputName(name string) bool {
+	if strings.Contains(output.Filename, "{{") {
+		return filepath.Ext(name) == filepath.Ext(output.Filename)
+	}
+	return name == filepath.Base(output.Filename)
+}
+
+// PruneDirTree finds generated output files (named as out.Filename and starting with GeneratedHeader)
+// from out.Directory (or out.OutputDir, if it's set) and its subdirectories, which don't belong
+// to any golang package anymore. Files are removed unless dryRun is true.
+// Returns list of files, which were (or would have been with dryRun) removed.
+func PruneDirTree(out OutputSettings, dryRun bool) ([]string, error) {
+	if out.Filename == "" {
+		return nil, ErrPruneNeedsOutput
+	}
+	root := out.Directory
+	if out.OutputDir != "" {
+		root = out.OutputDir
+	}
+	stale := []string{}
+	if !fileExists(root) {
+		return stale, nil
+	}
+	expected, err := out.outputFiles()
+	if err != nil {
+		return nil, fmt.Errorf("PruneDirTree failed: %w", err)
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !out.isOutputName(d.Name()) {
+			return err
+		}
+		fname, err := filepath.Abs(path)
+		if err != nil || expected[fname] {
+			return err
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil || !isGenerated(content) {
+			return err
+		}
+		stale = append(stale, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PruneDirTree failed: %w", err)
+	}
+	if dryRun {
+		return stale, nil
+	}
+	for _, fname := range stale {
+		slog.Debug("removing stale output file", "fname", fname)
+		if err = os.Remove(fname); err != nil {
+			return nil, fmt.Errorf("PruneDirTree failed: %w", err)
+		}
+	}
+	return stale, nil
+}
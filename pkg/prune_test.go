@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPruneDirTree verifies that only generated files without package are pruned.
+func TestPruneDirTree(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"doc.go":              "// Package foo is for testing.\npackage foo\n",
+		"README.md":           GeneratedHeader + "\n# foo\n",
+		"deleted/README.md":   GeneratedHeader + "\n# deleted\n",
+		"deleted/foo_test.go": "package deleted\n",
+		"manual/README.md":    "# Hand-written\n",
+	})
+	out := OutputSettings{Directory: dir, Filename: "README.md"}
+	expected := []string{dir + "/deleted/README.md"}
+	t.Run("dry run", func(t *testing.T) {
+		stale, err := PruneDirTree(out, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(stale, expected) {
+			t.Errorf("expected %v, received %v", expected, stale)
+		}
+		if !fileExists(expected[0]) {
+			t.Errorf("dry run removed %s", expected[0])
+		}
+	})
+	t.Run("prune", func(t *testing.T) {
+		stale, err := PruneDirTree(out, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(stale, expected) {
+			t.Errorf("expected %v, received %v", expected, stale)
+		}
+		if fileExists(expected[0]) {
+			t.Errorf("%s was not removed", expected[0])
+		}
+		if !fileExists(dir+"/README.md") || !fileExists(dir+"/manual/README.md") {
+			t.Error("too many files were removed")
+		}
+	})
+	t.Run("output dir", func(t *testing.T) {
+		dir := writeModule(t, map[string]string{
+			"doc.go":              "// Package foo is for testing.\npackage foo\n",
+			"out/README.md":       GeneratedHeader + "\n# foo\n",
+			"out/inner/README.md": GeneratedHeader + "\n# inner\n",
+		})
+		stale, err := PruneDirTree(OutputSettings{Directory: dir, OutputDir: dir + "/out", Filename: "README.md"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{dir + "/out/inner/README.md"}; !slices.Equal(stale, expected) {
+			t.Errorf("expected %v, received %v", expected, stale)
+		}
+	})
+	t.Run("filename template", func(t *testing.T) {
+		dir := writeModule(t, map[string]string{
+			"a/a.go":        "// Package a is for testing.\npackage a\n",
+			"a/a.md":        GeneratedHeader + "\n# a\n",
+			"a/notes.md":    "# Hand-written\n",
+			"deleted/b.md":  GeneratedHeader + "\n# b\n",
+			"docs/index.md": GeneratedHeader + "\n# index\n",
+		})
+		out := OutputSettings{Directory: dir, Filename: "{{ .Name }}.md", Index: "docs/index.md"}
+		stale, err := PruneDirTree(out, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{dir + "/deleted/b.md"}; !slices.Equal(stale, expected) {
+			t.Errorf("expected %v, received %v", expected, stale)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// resolver knows where current package is documented and
+// turns references to other packages and source files into links.
+type resolver struct {
+	imports    map[string]string   // import alias to import path
+	importPath string              // import path of current package
+	module     *module             // module, where current package belongs to
+	output     OutputSettings      // output settings of current package
+	sourceDir  string              // absolute path to directory of current package
+	outputFile string              // absolute path to output file of current package
+	typeParams map[string]bool     // type parameters in scope, which are not linked
+	fset       *token.FileSet      // positions of parsed source files
+	comments   []*ast.CommentGroup // comments from parsed source files
+	info       *types.Info         // type checked identifiers of current package
+	types      *types.Package      // type checked current package
+	pkg        *doc.Package        // documentation of current package
+	anchors    map[string]string   // declaration (e.g. "type Config") to its unique anchor
+}
+
+func newResolver(out OutputSettings, mod *module, importPath string, imports map[string]string) (*resolver, error) {
+	sourceDir, err := filepath.Abs(out.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("newResolver failed: %w", err)
+	}
+	res := &resolver{
+		imports: imports, importPath: importPath, module: mod, output: out, sourceDir: sourceDir,
+	}
+	if out.combinedFile != "" {
+		res.outputFile = out.combinedFile
+		return res, nil
+	}
+	if res.outputFile, err = out.outputPath(mod, importPath); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// relativeDir returns directory of package within module ("." for module root).
+func relativeDir(mod *module, importPath string) string {
+	if rel := strings.TrimPrefix(importPath, mod.path+"/"); rel != importPath {
+		return rel
+	}
+	return "."
+}
+
+// filename expands Filename template with .ImportPath, .Dir (relative to module root) and .Name
+// (last element of import path). Returns README.md, if Filename is not set.
+func (output *OutputSettings) filename(mod *module, importPath string) (string, error) {
+	switch {
+	case output.Filename == "":
+		return "README.md", nil
+	case !strings.Contains(output.Filename, "{{"):
+		return output.Filename, nil
+	}
+	tmpl, err := template.New("filename").Parse(output.Filename)
+	if err != nil {
+		return "", fmt.Errorf("OutputSettings.filename failed: %w", err)
+	}
+	fname := bytes.Buffer{}
+	err = tmpl.Execute(&fname, struct{ ImportPath, Dir, Name string }{
+		ImportPath: importPath, Dir: relativeDir(mod, importPath), Name: path.Base(importPath),
+	})
+	if err != nil {
+		return "", fmt.Errorf("OutputSettings.filename failed: %w", err)
+	}
+	return fname.String(), nil
+}
+
+// outputPath returns absolute path to output file of package with given import path.
+// Output files are next to golang files, unless OutputDir mirrors them into separate directory tree.
+// Filename with directory (e.g. "{{.ImportPath}}.md") is relative to module root or OutputDir.
+func (output *OutputSettings) outputPath(mod *module, importPath string) (string, error) {
+	fname, err := output.filename(mod, importPath)
+	if err != nil {
+		return "", err
+	}
+	root := mod.dir
+	if output.OutputDir != "" {
+		if root, err = filepath.Abs(output.OutputDir); err != nil {
+			return "", fmt.Errorf("OutputSettings.outputPath failed: %w", err)
+		}
+	}
+	if hasDir(fname) {
+		return filepath.Join(root, fname), nil
+	}
+	return filepath.Join(root, relativeDir(mod, importPath), fname), nil
+}
+
+// hasDir checks if filename has directory in it.
+func hasDir(fname string) bool {
+	return strings.ContainsRune(filepath.ToSlash(fname), '/')
+}
+
+// declAnchor returns unique anchor of declaration in current package (see getAnchors).
+// Without one, given anchor is returned as it is.
+func (res *resolver) declAnchor(decl, anchor string) string {
+	if res != nil {
+		if unique, ok := res.anchors[decl]; ok {
+			return unique
+		}
+	}
+	return anchor
+}
+
+// funcAnchor returns unique anchor of function or method heading.
+func (res *resolver) funcAnchor(funcObj doc.Func) string {
+	return res.declAnchor(funcDecl(funcObj), funcLink(funcObj))
+}
+
+// typeAnchor returns unique anchor of type heading.
+func (res *resolver) typeAnchor(name string) string {
+	return res.declAnchor("type "+name, intoLink("type "+name))
+}
+
+// withIdents returns copy of resolver, where given identifiers are known type parameters.
+func (res *resolver) withIdents(idents []*ast.Ident) *resolver {
+	if res == nil || len(idents) == 0 {
+		return res
+	}
+	scoped := *res
+	scoped.typeParams = maps.Clone(res.typeParams)
+	if scoped.typeParams == nil {
+		scoped.typeParams = map[string]bool{}
+	}
+	for _, ident := range idents {
+		scoped.typeParams[ident.Name] = true
+	}
+	return &scoped
+}
+
+// withTypeParams returns copy of resolver, where type parameters from fields are in scope.
+func (res *resolver) withTypeParams(fields *ast.FieldList) *resolver {
+	if fields == nil {
+		return res
+	}
+	idents := []*ast.Ident{}
+	for _, field := range fields.List {
+		idents = append(idents, field.Names...)
+	}
+	return res.withIdents(idents)
+}
+
+// inModule checks if importPath belongs to the same module as current package.
+func (res *resolver) inModule(importPath string) bool {
+	return importPath == res.module.path || strings.HasPrefix(importPath, res.module.path+"/")
+}
+
+// packageLink returns relative link from current output file to output file of importPath.
+func (res *resolver) packageLink(importPath string) string {
+	target, err := res.output.outputPath(res.module, importPath)
+	if err != nil {
+		slog.Warn("failed to resolve output file", "importPath", importPath, "err", err)
+		return ""
+	}
+	link, err := filepath.Rel(filepath.Dir(res.outputFile), target)
+	if err != nil {
+		slog.Warn("failed to resolve link", "from", res.outputFile, "to", target, "err", err)
+		return ""
+	}
+	return filepath.ToSlash(link)
+}
+
+// anchorLink returns link to anchor in documentation of importPath.
+// Within combined document, all anchors are in the same file.
+func (res *resolver) anchorLink(importPath, anchor string) string {
+	if res.output.combinedFile != "" {
+		return "#" + anchorPrefix(importPath) + "-" + anchor
+	}
+	return res.packageLink(importPath) + "#" + anchor
+}
+
+// sourceLink returns relative link from current output file to given line in source file.
+func (res *resolver) sourceLink(lineNumber LineNumber) string {
+	dir, err := filepath.Rel(filepath.Dir(res.outputFile), res.sourceDir)
+	if err != nil {
+		dir = res.sourceDir
+	}
+	return fmt.Sprintf("%s/%s#L%d", filepath.ToSlash(dir), lineNumber.Filename, lineNumber.Line)
+}
+
+// sourceText is fallback for syntax, which can't be rendered with links.
+// It returns node as it's written in source file and warns about it with file:line.
+// If source file can't be read, node is formatted with go/printer instead.
+func (res *resolver) sourceText(node ast.Node) varTypeOutput {
+	fset := token.NewFileSet()
+	if res != nil && res.fset != nil {
+		fset = res.fset
+	}
+	position, end := fset.Position(node.Pos()), fset.Position(node.End())
+	slog.Warn(
+		"Unsupported syntax, using source text",
+		"position", fmt.Sprintf("%s:%d", position.Filename, position.Line), "node", fmt.Sprintf("%T", node),
+	)
+	content, err := os.ReadFile(filepath.Clean(position.Filename))
+	if err == nil && position.Offset <= end.Offset && end.Offset <= len(content) {
+		return plain(string(content[position.Offset:end.Offset]))
+	}
+	text := bytes.Buffer{}
+	if err := printer.Fprint(&text, fset, node); err != nil {
+		slog.Warn("failed to print source text", "err", err)
+	}
+	return plain(text.String())
+}
+
+// refLink returns text with link to object, which expr (*ast.Ident or *ast.SelectorExpr) refers to.
+// Without type information, link is guessed from text by intoImportLink.
+func (res *resolver) refLink(expr ast.Expr, text string) string {
+	if res == nil {
+		return text
+	}
+	ident, ok := expr.(*ast.Ident)
+	if sel, isSel := expr.(*ast.SelectorExpr); isSel {
+		ident, ok = sel.Sel, true
+	}
+	if !ok || res.info == nil {
+		return intoImportLink(text, res)
+	}
+	if obj := res.info.Uses[ident]; obj != nil {
+		return res.objectLink(obj, text)
+	}
+	if field, ok := res.info.Defs[ident].(*types.Var); ok && field.Embedded() {
+		// embedded field of type, which couldn't be imported
+		return intoImportLink(text, res)
+	}
+	if res.info.Defs[ident] != nil { // e.g. type parameter in receiver
+		return text
+	}
+	return intoImportLink(text, res)
+}
+
+// objectLink returns text with link to documentation of type checked object.
+// Builtins, type parameters, fields and local names are not linked.
+func (res *resolver) objectLink(obj types.Object, text string) string {
+	if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+		return text
+	}
+	if obj.Pkg().Path() == res.importPath && !res.documented(obj) {
+		slog.Warn("Internal type: " + text)
+		return text
+	}
+	if url := res.objectURL(obj); url != "" {
+		return fmt.Sprintf(`<a href="%s">%s</a>`, url, text)
+	}
+	return text
+}
+
+// documented checks if object has its own documentation. Unexported objects are documented
+// only with OutputSettings.All and only within current package.
+func (res *resolver) documented(obj types.Object) bool {
+	if obj.Exported() {
+		return true
+	}
+	return res != nil && res.output.All && obj.Pkg() != nil && obj.Pkg().Path() == res.importPath
+}
+
+// objectURL returns URL to documentation of package level object.
+// Typed constants are documented under their type.
+// Returns empty string, if object isn't documented.
+func (res *resolver) objectURL(obj types.Object) string {
+	if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() || !res.documented(obj) {
+		return ""
+	}
+	importPath := obj.Pkg().Path()
+	anchor, decl := "", ""
+	switch obj.(type) {
+	case *types.TypeName:
+		anchor, decl = intoLink("type "+obj.Name()), "type "+obj.Name()
+	case *types.Func:
+		anchor, decl = intoLink("func "+obj.Name()), "func "+obj.Name()
+	case *types.Const:
+		anchor = "constants"
+		if named, ok := obj.Type().(*types.Named); ok && named.Obj().Pkg() == obj.Pkg() {
+			anchor = intoLink("type " + named.Obj().Name())
+		}
+	case *types.Var:
+		anchor = "variables"
+	default:
+		return ""
+	}
+	switch {
+	case importPath == res.importPath:
+		return "#" + res.declAnchor(decl, anchor)
+	case res.inModule(importPath):
+		return res.anchorLink(importPath, anchor)
+	}
+	return fmt.Sprintf("https://pkg.go.dev/%s#%s", importPath, obj.Name())
+}
@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestOutputDir verifies that output files and links follow OutputDir and Filename template.
+func TestOutputDir(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a is for testing.\npackage a\n\n// T is a type.\ntype T struct{}\n",
+		"b/b.go": "// Package b is for testing.\npackage b\n\nimport \"example.com/mod/a\"\n\n" +
+			"// New returns a.T.\nfunc New() a.T { return a.T{} }\n",
+	})
+	out := OutputSettings{Directory: dir, OutputDir: dir + "/docs", Filename: "{{ .Name }}.md"}
+	if err := RunDirTree(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(dir + "/docs/a/a.md") {
+		t.Error("docs/a/a.md is missing")
+	}
+	content, err := os.ReadFile(dir + "/docs/b/b.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<a href="../a/a.md#type-t">a.T</a>`,
+		"### func [New](../../b/b.go#L7)",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("%s is missing from %s", expected, string(content))
+		}
+	}
+	// filename with directory is placed under OutputDir as it is
+	out.Filename = "{{ .ImportPath }}.md"
+	if err := RunDirTree(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	if content, err = os.ReadFile(dir + "/docs/example.com/mod/b.md"); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<a href="a.md#type-t">a.T</a>`,
+		"### func [New](../../../b/b.go#L7)",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("%s is missing from %s", expected, string(content))
+		}
+	}
+	if fileExists(dir + "/docs/b/example.com/mod/b.md") {
+		t.Error("filename with directory was placed under package directory")
+	}
+}
+
+// TestSourceFallback verifies that unsupported syntax is printed as source text with warning.
+func TestSourceFallback(t *testing.T) {
+	dir := writeModule(t, map[string]string{"fb.go": `// Package fb has literals with formatting verbs.
+package fb
+
+// Format values.
+const (
+	Format  = "%d%%"
+	Percent = 10 % 3
+)
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{`const Format = "%d%%"`, "const Percent = 10 % 3"} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+	// syntax without structured rendering is printed as source text with warning
+	fname := dir + "/bad.go"
+	if err := os.WriteFile(fname, []byte("package fb\n\nvar X = 1 +  /* one */ 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fname, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	logs := bytes.Buffer{}
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	bad := &ast.BadExpr{From: spec.Values[0].Pos(), To: spec.Values[0].End()}
+	if vto := variableType(bad, 0, false, &resolver{fset: fset}); vto.plainText != "1 +  /* one */ 1" {
+		t.Errorf("unexpected source text: %#v", vto)
+	}
+	if !strings.Contains(logs.String(), "bad.go:3") {
+		t.Errorf("warning with file:line is missing from %s", logs.String())
+	}
+}
+
+// TestTypeResolution verifies that identifiers are linked through imports of their own file, dot imports and local types.
+func TestTypeResolution(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a.go": "// Package res resolves identifiers.\npackage res\n\nimport str \"strings\"\n\n// A uses strings.\nfunc A(b *str.Builder) {}\n",
+		"b.go": "package res\n\nimport str \"bytes\"\n\n// B uses bytes.\nfunc B(b *str.Buffer) {}\n",
+		"c.go": "package res\n\nimport . \"io\"\n\n// C uses dot import.\nfunc C(r Reader) {}\n",
+		"d.go": `package res
+
+// Buffer is local buffer.
+type Buffer struct{}
+
+// Limit is constant.
+const Limit = 10
+
+// D uses builtins and local types.
+func D(r rune, u uint, a any, c complex128, b Buffer) [Limit]int { return [Limit]int{} }
+`,
+	})
+	logs := bytes.Buffer{}
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		`func A(b *<a href="https://pkg.go.dev/strings#Builder">str.Builder</a>)`,
+		`func B(b *<a href="https://pkg.go.dev/bytes#Buffer">str.Buffer</a>)`,
+		`func C(r <a href="https://pkg.go.dev/io#Reader">Reader</a>)`,
+		`func D(r rune, u uint, a any, c complex128, b <a href="#type-buffer">Buffer</a>) [Limit]int`,
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+	if strings.Contains(logs.String(), "Internal type") {
+		t.Errorf("unexpected warnings: %s", logs.String())
+	}
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"bytes"
 	_ "embed"
 	"errors"
 	"fmt"
@@ -8,40 +9,105 @@ import (
 	"go/doc"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
 	"text/template"
 )
 
 type OutputSettings struct {
-	Default   io.WriteCloser // current default
-	Directory string         // override Default with Directory + Filename
-	Filename  string         // override Default with Directory + Filename
+	Default    io.WriteCloser // current default
+	Directory  string         // override Default with Directory + Filename
+	Filename   string         // override Default with Directory + Filename, can use {{.ImportPath}}, {{.Dir}} and {{.Name}}
+	OutputDir  string         // mirror output files into this directory tree instead of Directory
+	Template   string         // template file, which can redefine blocks from built-in Markdown
+	Check      bool           // compare output with Directory + Filename instead of writing it
+	Markers    bool           // replace only content between MarkerBegin and MarkerEnd in output file
+	Force      bool           // overwrite output file, even if it doesn't start with GeneratedHeader
+	Combined   bool           // RunDirTree writes all packages into one document in Directory + Filename
+	Index      string         // RunDirTree writes module index into Directory + Index
+	FieldTable bool           // render table of fields under each struct type
+
+	ExpandInterfaces   bool // list methods of embedded interfaces under interface type
+	CollapseDeprecated bool // hide docs of deprecated symbols behind their deprecation paragraph
+
+	NoteMarkers []string // markers (e.g. "TODO"), whose notes are rendered in addition to DefaultNoteMarkers
+	All         bool     // document also unexported identifiers (doc.AllDecls)
+
+	IndexInternal IndexMode // how packages under internal directories are shown in module index
+	IndexMain     IndexMode // how main packages are shown in module index
+
+	combinedFile string // absolute path to combined document, when Combined is used
 }
 
-type lineNumber struct {
-	filename string
-	line     int
+// LineNumber tells where function or type has been declared.
+type LineNumber struct {
+	Filename string // filename without directory
+	Line     int    // line number within file
 }
 
 type packageInfo struct {
 	pkg         doc.Package
+	files       []string
 	imports     map[string]string
-	lineNumbers map[string]lineNumber
+	fileImports map[string][]string
+	anchors     map[string]string
+	lineNumbers map[string]LineNumber
+	fset        *token.FileSet
+	comments    []*ast.CommentGroup
+	types       *types.Package
+	info        *types.Info
+	// type name to constant name to value from String() method
+	stringValues map[string]map[string]string
+}
+
+// packageOutput has rendered documentation for one package.
+type packageOutput struct {
+	data    *TemplateData
+	out     OutputSettings // Directory and Filename point to actual output file
+	tmpl    *template.Template
+	content []byte
 }
 
+// TemplateData is given to template, when it is executed.
+// It embeds doc.Package, so all its fields (e.g. .Name, .Doc, .Funcs and .Types) are
+// available in template as such.
+type TemplateData struct {
+	doc.Package
+	ImportPath  string                // import path of the package
+	ModulePath  string                // module path from go.mod
+	GoVersion   string                // go version from go.mod
+	Files       []string              // golang files that were documented
+	Output      string                // output filename or empty, if output goes to Default writer
+	ImportMap   map[string]string     // import alias to import path over all files in package
+	FileImports map[string][]string   // golang file (e.g. "run.go") to import paths in it
+	LineNumbers map[string]LineNumber // link anchor (e.g. "func-rundirtree") to its declaration
+}
+
+// GeneratedHeader is the first line in every generated file.
+// Output files without it are considered hand-written and they are not overwritten without Force.
+const GeneratedHeader = "<!-- Code generated by go2md. DO NOT EDIT. -->"
+
 var (
-	// Markdown is golang template for go2md output
+	// Markdown is golang template for go2md output.
+	// It is split into blocks (package, which has title, overview, index, deprecated, examples, constants,
+	// variables, functions, types and notes and finally footer), which can be redefined in OutputSettings.Template.
+	// Combined document renders each package with section template, which is the package block,
+	// unless Template file replaces whole output.
 	//
 	//go:embed template.md
 	Markdown             string // value from template.md file
 	ErrManyPackagesInDir = errors.New("can only handle one package per directory")
 	ErrNoPackageFound    = errors.New("couldn't find package from ")
+	ErrOutputOutdated    = errors.New("output is outdated")
+	ErrCheckNeedsOutput  = errors.New("check needs output filename")
+	ErrNotGenerated      = errors.New("refusing to overwrite file without go2md header")
+	ErrPruneNeedsOutput  = errors.New("prune needs output filename")
 )
 
 // isProductionGo ignores all code that is only used for `go test`
@@ -73,41 +139,107 @@ func getImportsFromFile(specs []*ast.ImportSpec) map[string]string {
 // If alias is `_`, we ignore it.
 func getImports(astPackages map[string]*ast.Package) map[string]string {
 	mapping := map[string]string{}
-	// stats["pkg"]["github.com/foo/bar"] = []string{"foo.go", "bar.go"}
-	stats := map[string]map[string][]string{}
 	for _, astPkg := range astPackages {
-		for fname, f := range astPkg.Files {
+		for _, f := range astPkg.Files {
 			for alias, fullName := range getImportsFromFile(f.Imports) {
 				mapping[alias] = fullName
-				if _, ok := stats[alias]; !ok {
-					stats[alias] = map[string][]string{}
-				}
-				if _, ok := stats[alias][fullName]; !ok {
-					stats[alias][fullName] = []string{}
-				}
-				stats[alias][fullName] = append(stats[alias][fullName], fname)
 			}
 		}
 	}
-	for alias := range stats {
-		if len(stats[alias]) == 1 {
-			continue
+	return mapping
+}
+
+// getFileImports creates map from golang file name to import paths in it (in source order).
+func getFileImports(astPackages map[string]*ast.Package) map[string][]string {
+	fileImports := map[string][]string{}
+	for _, astPkg := range astPackages {
+		for fname, f := range astPkg.Files {
+			paths := []string{}
+			for _, spec := range f.Imports {
+				paths = append(paths, strings.Trim(spec.Path.Value, "\""))
+			}
+			fileImports[filepath.Base(fname)] = paths
 		}
-		duplicates := []string{}
-		for fullName, fname := range stats[alias] {
-			duplicates = append(duplicates, fmt.Sprintf("%s in %s", fullName, strings.Join(fname, ", ")))
+	}
+	return fileImports
+}
+
+// parseTemplate parses built-in Markdown and layers Template file on top of it.
+// Template file can either replace whole output or just redefine some of the blocks.
+func (output *OutputSettings) parseTemplate(funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New("new").Funcs(funcs).Parse(Markdown)
+	if err != nil || output.Template == "" {
+		return tmpl, err
+	}
+	content, err := os.ReadFile(filepath.Clean(output.Template))
+	if err != nil {
+		return nil, fmt.Errorf("OutputSettings.parseTemplate failed: %w", err)
+	}
+	builtin := tmpl.Tree
+	if _, err = tmpl.Parse(string(content)); err != nil {
+		return nil, err
+	}
+	if tmpl.Tree != builtin {
+		// Template file replaced whole output, so it renders packages in combined document too
+		if _, err = tmpl.AddParseTree("section", tmpl.Tree); err != nil {
+			return nil, err
 		}
-		slog.Warn(fmt.Sprintf("%s has been imported as \n- %s", alias, strings.Join(duplicates, "\n- ")))
 	}
-	return mapping
+	return tmpl, nil
+}
+
+// check compares generated content with existing output file.
+// If they differ, it writes unified diff into Default writer and returns ErrOutputOutdated.
+func (output *OutputSettings) check(generated []byte) error {
+	if output.Filename == "" {
+		return ErrCheckNeedsOutput
+	}
+	fname := filepath.Clean(output.Directory + "/" + output.Filename)
+	existing, err := readExisting(fname)
+	if err != nil {
+		return fmt.Errorf("OutputSettings.check failed: %w", err)
+	}
+	if output.Markers {
+		if generated, err = injectMarkers(fname, existing, generated); err != nil {
+			return err
+		}
+	}
+	if bytes.Equal(existing, generated) {
+		return nil
+	}
+	_, _ = output.Default.Write([]byte(unifiedDiff(fname, string(existing), string(generated))))
+	return fmt.Errorf("%w: %s", ErrOutputOutdated, fname)
+}
+
+// isGenerated checks if content starts with GeneratedHeader.
+func isGenerated(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(GeneratedHeader))
 }
 
-// Output creates output file if needed and returns writer to it
+// Output creates output file if needed and returns writer to it.
+// With Markers, output file is updated only when writer is closed.
+// Without Markers and Force, it returns ErrNotGenerated, if existing output file
+// doesn't start with GeneratedHeader.
 func (output *OutputSettings) Writer() (io.WriteCloser, error) {
 	if output.Filename == "" {
 		return output.Default, nil
 	}
 	fname := output.Directory + "/" + output.Filename
+	if err := os.MkdirAll(filepath.Dir(filepath.Clean(fname)), 0o750); err != nil {
+		return output.Default, fmt.Errorf("OutputSettings.Writer failed: %w", err)
+	}
+	if output.Markers {
+		return &markerWriter{fname: fname}, nil
+	}
+	if !output.Force {
+		existing, err := readExisting(fname)
+		if err != nil {
+			return output.Default, fmt.Errorf("OutputSettings.Writer failed: %w", err)
+		}
+		if len(existing) > 0 && !isGenerated(existing) {
+			return output.Default, fmt.Errorf("%w %s", ErrNotGenerated, fname)
+		}
+	}
 	fout, err := os.Create(filepath.Clean(fname))
 	if err != nil {
 		return output.Default, fmt.Errorf("OutputSettings.Writer failed: %w", err)
@@ -118,77 +250,84 @@ func (output *OutputSettings) Writer() (io.WriteCloser, error) {
 // RunDirectory checks given directory and only that directory
 // Returns ErrNoPackagesFound if includeMain=true and current directory has only main package.
 func RunDirectory(out OutputSettings, version string, includeMain bool) error {
-	pkgName, err := getPackageName(out.Directory)
+	return runDirectory(out, version, includeMain, sourceImporters{})
+}
+
+// runDirectory is RunDirectory, which shares importers with other packages of the same run.
+func runDirectory(out OutputSettings, version string, includeMain bool, importers sourceImporters) error {
+	pkgName, mod, err := getPackageName(out.Directory)
 	if err != nil {
 		return err
 	}
-	return run(out, pkgName, version, includeMain)
+	importers.forModule(mod)
+	return run(out, mod, pkgName, version, includeMain)
 }
 
 // RunDirTree checks given directory and its subdirectories with RunDirectory().
 // Ignores all ErrNoPackageFound errors from RunDirectory.
+// ErrOutputOutdated errors are collected and returned after all directories have been checked.
+// If Index is set, module index is written after packages.
 func RunDirTree(out OutputSettings, version string, includeMain bool) error {
-	paths := map[string]bool{}
+	paths := []string{}
 	err := filepath.WalkDir(out.Directory, func(path string, d fs.DirEntry, err error) error {
-		if !d.IsDir() && strings.HasSuffix(path, ".go") {
-			paths[filepath.Dir(path)] = true
+		if !d.IsDir() && strings.HasSuffix(path, ".go") && !slices.Contains(paths, filepath.Dir(path)) {
+			paths = append(paths, filepath.Dir(path))
 		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
-	for path := range paths {
+	slices.Sort(paths)
+	importers := sourceImporters{}
+	if out.Combined {
+		err = runCombined(out, version, includeMain, paths, importers)
+	} else {
+		err = runPackages(out, version, includeMain, paths, importers)
+	}
+	if out.Index == "" || (err != nil && !errors.Is(err, ErrOutputOutdated)) {
+		return err
+	}
+	return errors.Join(err, writeIndex(out, version, includeMain, paths))
+}
+
+// runPackages runs RunDirectory for every path.
+// Ignores all ErrNoPackageFound errors from RunDirectory.
+// ErrOutputOutdated errors are collected and returned after all directories have been checked.
+func runPackages(out OutputSettings, version string, includeMain bool, paths []string, importers sourceImporters) error {
+	outdated := []error{}
+	for _, path := range paths {
 		out.Directory = path
-		if err = RunDirectory(out, version, includeMain); err != nil {
-			if errors.Is(err, ErrNoPackageFound) {
+		if err := runDirectory(out, version, includeMain, importers); err != nil {
+			switch {
+			case errors.Is(err, ErrNoPackageFound):
 				slog.Warn("failed to find package from " + path)
 				continue
+			case errors.Is(err, ErrOutputOutdated):
+				outdated = append(outdated, err)
+				continue
 			}
 			return err
 		}
 	}
-	return nil
-}
-
-// isExported checks if given pattern (typically var, const, type or function name)
-// can be used from other packages.
-func isExported(pattern string) bool {
-	ok, _ := regexp.Match("^[A-Z]", []byte(pattern))
-	return ok
+	return errors.Join(outdated...)
 }
 
-// getLineNumbers goes through given file and builds map that gives line number for every
-// function and type in a file.
-func getLineNumbers(filename string) map[string]int {
-	content, err := os.ReadFile(filepath.Clean(filename))
-	if err != nil {
-		slog.Error("scanFile", "err", err, "filename", filename)
-		return nil
+// getLineNumbers builds map that gives line number for every function, method and type in package.
+// Keys are the unique anchors from getAnchors, which funcHeading and typeHeading use.
+func getLineNumbers(fset *token.FileSet, pkg *doc.Package, anchors map[string]string) map[string]LineNumber {
+	lineNumbers := map[string]LineNumber{}
+	add := func(key string, pos token.Pos) {
+		position := fset.Position(pos)
+		lineNumbers[key] = LineNumber{Filename: filepath.Base(position.Filename), Line: position.Line}
 	}
-	lineNumbers := map[string]int{}
-	for lineNumber, line := range strings.Split(string(content), "\n") {
-		if strings.HasPrefix(line, "func ") {
-			words := strings.Split(line, " ")
-			if len(words) >= 4 && strings.HasPrefix(words[1], "(") && isExported(words[3]) {
-				words[3] = strings.Split(words[3], "[")[0]
-				words[3] = strings.Split(words[3], "(")[0]
-				key := intoLink(strings.Join(words[0:4], " "))
-				lineNumbers[key] = lineNumber + 1
-			}
-			if len(words) >= 2 && isExported(words[1]) {
-				words[1] = strings.Split(words[1], "[")[0]
-				words[1] = strings.Split(words[1], "(")[0]
-				key := intoLink(strings.Join(words[0:2], " "))
-				lineNumbers[key] = lineNumber + 1
-			}
-		}
-		if strings.HasPrefix(line, "type ") {
-			words := strings.Split(line, " ")
-			if len(words) >= 2 && isExported(words[1]) {
-				lineNumbers[intoLink(strings.Join(words[0:2], " "))] = lineNumber + 1
-			}
-		}
+	funcs := slices.Clone(pkg.Funcs)
+	for _, typeObj := range pkg.Types {
+		add(anchors["type "+typeObj.Name], typeObj.Decl.Pos())
+		funcs = append(append(funcs, typeObj.Funcs...), typeObj.Methods...)
+	}
+	for _, funcObj := range funcs {
+		add(anchors[funcDecl(*funcObj)], funcObj.Decl.Pos())
 	}
 	return lineNumbers
 }
@@ -200,33 +339,49 @@ func getLineNumbers(filename string) map[string]int {
 // If directory has references to more than one package, that is error because
 // multiple packages would overwrite each others output.
 // If includeMain is false and directory has main package, it returns ErrNoPackageFound
-func getPackage(directory, modName string, includeMain bool) (*packageInfo, error) {
-	pkgInfo := &packageInfo{lineNumbers: map[string]lineNumber{}}
+// Mode is passed to doc.NewFromFiles (e.g. doc.AllDecls for unexported identifiers).
+func getPackage(directory string, mod *module, modName string, includeMain bool, mode doc.Mode) (*packageInfo, error) {
+	pkgInfo := &packageInfo{}
 	pkgs := []doc.Package{}
 	fset := token.NewFileSet()
 	if !fileExists(directory + "/doc.go") {
 		slog.Warn("doc.go is missing from " + directory)
 	}
 	astPackages, err := parser.ParseDir(fset, directory, func(fi fs.FileInfo) bool {
-		fname := directory + "/" + fi.Name()
-		if valid := isProductionGo(fname); !valid {
+		if valid := isProductionGo(fi.Name()); !valid {
 			return false
 		}
-		for key, value := range getLineNumbers(fname) {
-			pkgInfo.lineNumbers[key] = lineNumber{filename: fi.Name(), line: value}
-		}
+		pkgInfo.files = append(pkgInfo.files, fi.Name())
 		return true
 	}, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
 	pkgInfo.imports = getImports(astPackages)
+	pkgInfo.fileImports = getFileImports(astPackages)
 	for _, astPkg := range astPackages {
-		pkg := doc.New(astPkg, directory, 0)
-		if pkg.Name == "main" && !includeMain {
+		for _, file := range astPkg.Files {
+			// doc.New removes comments from AST
+			pkgInfo.comments = append(pkgInfo.comments, file.Comments...)
+		}
+	}
+	for _, astPkg := range astPackages {
+		if astPkg.Name == "main" && !includeMain {
 			slog.Warn("Ignoring main package due to --ignore-main")
 			continue
 		}
+		// type check before doc.New, because it modifies AST
+		// with many packages, these are overwritten, but that is error anyway
+		pkgInfo.types, pkgInfo.info = checkTypes(fset, mod, modName, astPkg)
+		pkgInfo.stringValues = getStringValues(astPkg, pkgInfo.info)
+		testFiles, err := getTestFiles(fset, directory, astPkg.Name)
+		if err != nil {
+			return nil, err
+		}
+		pkg, err := doc.NewFromFiles(fset, append(packageFiles(astPkg), testFiles...), directory, mode)
+		if err != nil {
+			return nil, fmt.Errorf("doc.NewFromFiles failed: %w", err)
+		}
 		// log.WithFields(log.Fields{"pkg": fmt.Sprintf("%#v", pkg)}).Info("output from doc.New")
 		// log.WithFields(log.Fields{"pkg.Types": fmt.Sprintf("%#v: %#v", fset.Position(token.Pos(pkg.Types[0].Decl.Tok)), pkg.Types[0])}).Info("output from doc.New")
 		if strings.HasSuffix(modName, "/"+pkg.Name) {
@@ -239,6 +394,9 @@ func getPackage(directory, modName string, includeMain bool) (*packageInfo, erro
 		return nil, fmt.Errorf("%w %s", ErrNoPackageFound, directory)
 	case 1:
 		pkgInfo.pkg = pkgs[0]
+		pkgInfo.anchors = getAnchors(&pkgInfo.pkg)
+		pkgInfo.lineNumbers = getLineNumbers(fset, &pkgInfo.pkg, pkgInfo.anchors)
+		pkgInfo.fset = fset
 		return pkgInfo, nil
 	}
 	names := []string{}
@@ -248,41 +406,95 @@ func getPackage(directory, modName string, includeMain bool) (*packageInfo, erro
 	return nil, fmt.Errorf("%w (found: %s)", ErrManyPackagesInDir, strings.Join(names, ", "))
 }
 
-// Run reads all "*.go" files (excluding "*_test.go") and writes markdown document out of it.
-func run(out OutputSettings, modName, version string, includeMain bool) (err error) {
+// render reads all "*.go" files from out.Directory and executes named template for them.
+// "*_test.go" files are only used for examples.
+// Returns nil, if package is main and includeMain is false.
+func render(out OutputSettings, mod *module, modName, version, name string, includeMain bool) (pkgOut *packageOutput, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v", r)
 		}
 	}()
 	var pkgInfo *packageInfo
-	if pkgInfo, err = getPackage(out.Directory, modName, includeMain); err != nil {
-		err = fmt.Errorf("getPackages failed: %w", err)
-		return
+	mode := doc.Mode(0)
+	if out.All {
+		mode = doc.AllDecls
 	}
-	pkgInfo.imports["main"] = modName
-	funcs := templateFuncs(version, pkgInfo.imports, pkgInfo.lineNumbers)
-	tmpl, err := template.New("new").Funcs(funcs).Parse(Markdown)
+	if pkgInfo, err = getPackage(out.Directory, mod, modName, includeMain, mode); err != nil {
+		return nil, fmt.Errorf("getPackages failed: %w", err)
+	}
+	res, err := newResolver(out, mod, modName, pkgInfo.imports)
+	if err != nil {
+		return nil, err
+	}
+	res.fset, res.comments, res.info = pkgInfo.fset, pkgInfo.comments, pkgInfo.info
+	res.pkg, res.types, res.anchors = &pkgInfo.pkg, pkgInfo.types, pkgInfo.anchors
+	// from now on, Directory and Filename point to actual output file
+	if out.Filename != "" || out.OutputDir != "" {
+		if out.Filename, err = out.filename(mod, modName); err != nil {
+			return nil, err
+		}
+		if out.OutputDir != "" || hasDir(out.Filename) {
+			out.Directory, out.Filename = filepath.Split(res.outputFile)
+		}
+	}
+	data := &TemplateData{
+		Package:     pkgInfo.pkg,
+		ImportPath:  modName,
+		ModulePath:  mod.path,
+		GoVersion:   mod.goVersion,
+		Files:       pkgInfo.files,
+		Output:      out.Filename,
+		ImportMap:   pkgInfo.imports,
+		FileImports: pkgInfo.fileImports,
+		LineNumbers: pkgInfo.lineNumbers,
+	}
+	funcs := templateFuncs(version, res, pkgInfo.lineNumbers, pkgInfo.stringValues)
+	tmpl, err := out.parseTemplate(funcs)
 	if err != nil {
-		err = fmt.Errorf("tmpl.New failed: %w", err)
-		return
+		return nil, fmt.Errorf("tmpl.Parse failed: %w", err)
 	}
 	if pkgInfo.pkg.Name == "main" && !includeMain {
-		err = nil
-		return
+		return nil, nil
+	}
+	content := bytes.Buffer{}
+	if err = tmpl.ExecuteTemplate(&content, name, data); err != nil {
+		return nil, fmt.Errorf("tmpl.Execute failed: %w", err)
+	}
+	return &packageOutput{data: data, out: out, tmpl: tmpl, content: content.Bytes()}, nil
+}
+
+// save writes generated content into output or with Check, compares it against output.
+// GeneratedHeader is added in front of content, when it's written into file without Markers.
+func (output *OutputSettings) save(generated []byte) (err error) {
+	if !output.Markers && output.Filename != "" {
+		generated = append([]byte(GeneratedHeader+"\n"), generated...)
+	}
+	if output.Check {
+		return output.check(generated)
 	}
-	writer, err := out.Writer()
+	writer, err := output.Writer()
 	if err != nil {
-		return
+		return err
 	}
 	defer func() {
-		if out.Filename != "" {
-			_ = writer.Close()
+		if output.Filename == "" {
+			return
+		}
+		if errClose := writer.Close(); err == nil {
+			err = errClose
 		}
 	}()
-	if err = tmpl.Execute(writer, pkgInfo.pkg); err != nil {
-		err = fmt.Errorf("tmpl.Execute failed: %w", err)
-		return
+	_, err = writer.Write(generated)
+	return err
+}
+
+// run reads all "*.go" files and writes markdown document out of it.
+// "*_test.go" files are only used for examples.
+func run(out OutputSettings, mod *module, modName, version string, includeMain bool) error {
+	pkgOut, err := render(out, mod, modName, version, "new", includeMain)
+	if err != nil || pkgOut == nil {
+		return err
 	}
-	return nil
+	return pkgOut.out.save(pkgOut.content)
 }
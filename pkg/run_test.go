@@ -2,7 +2,10 @@ package pkg
 
 import (
 	"bytes"
+	"errors"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -44,7 +47,7 @@ func TestRun(t *testing.T) {
 		if err != nil {
 			t.Error("run() returned err: " + err.Error())
 		}
-		expected := strings.TrimSpace(string(readme))
+		expected := strings.TrimSpace(strings.TrimPrefix(string(readme), GeneratedHeader+"\n"))
 		received := strings.TrimSpace(wc.String())
 		if expected != received {
 			t.Error("outputs don't match")
@@ -82,7 +85,7 @@ func TestRun(t *testing.T) {
 		if err != nil {
 			t.Error("run() returned err: " + err.Error())
 		}
-		expected := strings.TrimSpace(string(readme))
+		expected := strings.TrimSpace(strings.TrimPrefix(string(readme), GeneratedHeader+"\n"))
 		received := strings.TrimSpace(wc.String())
 		if expected != received {
 			t.Error("outputs don't match")
@@ -104,3 +107,235 @@ func TestRun(t *testing.T) {
 		}
 	})
 }
+
+// writeModule creates temporary go module with given files and returns its directory.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/mod\n\ngo 1.21\n"
+	for fname, content := range files {
+		fname = filepath.Join(dir, fname)
+		if err := os.MkdirAll(filepath.Dir(fname), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fname, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestCheck verifies that OutputSettings.Check detects outdated output files.
+func TestCheck(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"doc.go": "// Package foo is for testing.\npackage foo\n",
+	})
+	var wc writeCloser
+	out := OutputSettings{Default: &wc, Directory: dir, Filename: "README.md"}
+	if err := RunDirectory(out, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	out.Check = true
+	t.Run("up to date", func(t *testing.T) {
+		if err := RunDirTree(out, "test", true); err != nil {
+			t.Errorf("RunDirTree returned %v", err)
+		}
+		if wc.String() != "" {
+			t.Errorf("unexpected diff: %s", wc.String())
+		}
+	})
+	t.Run("outdated", func(t *testing.T) {
+		if err := RunDirTree(out, "other", true); !errors.Is(err, ErrOutputOutdated) {
+			t.Errorf("RunDirTree should return ErrOutputOutdated instead of %v", err)
+		}
+		expected := "@@ -21,4 +21,4 @@\n \n --\n \n-Generated by [github.com/jylitalo/go2md](https://github.com/jylitalo/go2md/) vtest\n" +
+			"+Generated by [github.com/jylitalo/go2md](https://github.com/jylitalo/go2md/) vother\n"
+		if !strings.Contains(wc.String(), expected) {
+			t.Errorf("diff is missing %#v from %s", expected, wc.String())
+		}
+	})
+	t.Run("output is required", func(t *testing.T) {
+		out.Filename = ""
+		if err := RunDirectory(out, "test", true); !errors.Is(err, ErrCheckNeedsOutput) {
+			t.Errorf("RunDirectory should return ErrCheckNeedsOutput instead of %v", err)
+		}
+	})
+}
+
+// TestForce verifies that hand-written files are overwritten only with OutputSettings.Force.
+func TestForce(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"doc.go":    "// Package foo is for testing.\npackage foo\n",
+		"README.md": "# Hand-written\n",
+	})
+	var wc writeCloser
+	out := OutputSettings{Default: &wc, Directory: dir, Filename: "README.md"}
+	t.Run("refuse hand-written", func(t *testing.T) {
+		if err := RunDirTree(out, "test", true); !errors.Is(err, ErrNotGenerated) {
+			t.Errorf("RunDirTree should return ErrNotGenerated instead of %v", err)
+		}
+	})
+	t.Run("force", func(t *testing.T) {
+		out.Force = true
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		out.Force = false
+		content, err := os.ReadFile(dir + "/README.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(content), GeneratedHeader+"\n# foo\n") {
+			t.Errorf("generated header is missing from %#v", string(content))
+		}
+	})
+	t.Run("overwrite generated", func(t *testing.T) {
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Errorf("RunDirectory failed to overwrite generated file: %v", err)
+		}
+	})
+}
+
+// TestAll verifies that OutputSettings.All documents unexported identifiers.
+func TestAll(t *testing.T) {
+	dir := writeModule(t, map[string]string{"all.go": `// Package all has unexported identifiers.
+package all
+
+// config is internal.
+type config struct {
+	name string
+}
+
+// newConfig creates config.
+func newConfig() *config { return &config{} }
+
+// reset resets.
+func (c *config) reset() {}
+
+// Run runs.
+func Run(c *config) {}
+`})
+	defer slog.SetDefault(slog.Default())
+	for _, all := range []bool{false, true} {
+		logs := bytes.Buffer{}
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+		var wc writeCloser
+		if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir, All: all}, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		received := wc.String()
+		for _, expected := range []string{
+			"- [type config](#type-config) _(unexported)_\n" +
+				"    - [func newConfig() *config](#func-newconfig) _(unexported)_\n" +
+				"    - [func (c *config) reset()](#func-c-config-reset) _(unexported)_\n",
+			"### <em>type [config](./all.go#L5)</em>\n",
+			"### <em>func [newConfig](./all.go#L10)</em>\n",
+			"### <em>func (c *config) [reset](./all.go#L13)</em>\n",
+			`func Run(c *<a href="#type-config">config</a>)`,
+			"| <a href=\"#func-c-config-reset\">reset</a> | pointer |  |",
+		} {
+			if strings.Contains(received, expected) != all {
+				t.Errorf("All=%v: %#v in %s", all, expected, received)
+			}
+		}
+		if strings.Contains(logs.String(), "Internal type") == all {
+			t.Errorf("All=%v: warnings: %s", all, logs.String())
+		}
+	}
+}
+
+// TestDuplicateAnchors verifies that declarations with the same anchor get their own line numbers
+// and numbered anchors like GitHub gives for duplicate headings.
+func TestDuplicateAnchors(t *testing.T) {
+	dir := writeModule(t, map[string]string{"dup.go": `// Package dup has names, which differ only by case.
+package dup
+
+// Builder builds.
+type Builder struct{}
+
+// Grow grows.
+func (b *Builder) Grow(n int) {}
+
+// grow grows without checks.
+func (b *Builder) grow(n int) {}
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir, All: true}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"    - [func (b *Builder) Grow(n int)](#func-b-builder-grow)\n" +
+			"    - [func (b *Builder) grow(n int)](#func-b-builder-grow-1) _(unexported)_\n",
+		"### func (b *Builder) [Grow](./dup.go#L8)\n",
+		"### <em>func (b *Builder) [grow](./dup.go#L11)</em>\n",
+		`| <a href="#func-b-builder-grow">Grow</a> | pointer |  |`,
+		`| <a href="#func-b-builder-grow-1">grow</a> | pointer |  |`,
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+	// anchors in combined document are numbered before they are prefixed
+	section := combineSection("# dup\n## func Grow\n## func grow\n[grow](#func-grow-1)\n", "dup")
+	if !strings.Contains(section, `<a name="dup-func-grow-1"></a>`) || !strings.Contains(section, "[grow](#dup-func-grow-1)") {
+		t.Errorf("numbered anchor is missing from %s", section)
+	}
+}
+
+// TestTemplate checks that OutputSettings.Template replaces built-in template.
+func TestTemplate(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"doc.go":      "// Package foo is for testing.\npackage foo\n",
+		"custom.tmpl": "custom {{ .Name }}: {{ trim .Doc }}\n",
+		"footer.tmpl": "{{ define \"footer\" }}custom footer{{ end }}\n",
+		"foo.go":      "package foo\n\nimport \"fmt\"\n\nfunc Foo() { fmt.Println() }\n",
+		"data.tmpl": "{{ .ImportPath }} {{ .ModulePath }} {{ .GoVersion }} {{ .Files }}" +
+			"{{ range $k, $v := .ImportMap }} {{ $k }}={{ $v }}{{ end }}" +
+			"{{ with index .LineNumbers \"func-foo\" }} {{ .Filename }}:{{ .Line }}{{ end }}" +
+			"{{ range $f, $paths := .FileImports }} {{ $f }}={{ $paths }}{{ end }}\n",
+	})
+	t.Run("custom template", func(t *testing.T) {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, Template: dir + "/custom.tmpl"}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		expected := "custom foo: Package foo is for testing.\n"
+		if wc.String() != expected {
+			t.Errorf("expected %#v, received %#v", expected, wc.String())
+		}
+	})
+	t.Run("redefine footer block", func(t *testing.T) {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, Template: dir + "/footer.tmpl"}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		received := wc.String()
+		if !strings.HasPrefix(received, "# foo\n\n## Overview\nPackage foo is for testing.") {
+			t.Errorf("built-in blocks are missing from %#v", received)
+		}
+		if !strings.HasSuffix(received, "\n\ncustom footer\n") {
+			t.Errorf("custom footer is missing from %#v", received)
+		}
+	})
+	t.Run("template data", func(t *testing.T) {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, Filename: "", Template: dir + "/data.tmpl"}
+		if err := RunDirectory(out, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		expected := "example.com/mod example.com/mod 1.21 [doc.go foo.go] fmt=fmt foo.go:5 doc.go=[] foo.go=[fmt]\n"
+		if wc.String() != expected {
+			t.Errorf("expected %#v, received %#v", expected, wc.String())
+		}
+	})
+	t.Run("missing template", func(t *testing.T) {
+		var wc writeCloser
+		out := OutputSettings{Default: &wc, Directory: dir, Template: dir + "/missing.tmpl"}
+		if err := RunDirectory(out, "test", true); err == nil {
+			t.Error("RunDirectory should fail with missing template")
+		}
+	})
+}
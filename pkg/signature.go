@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log/slog"
+	"strings"
+)
+
+// signature prints function declaration without body and doc comment the same way as gofmt does.
+// Comments within declaration are kept. Without fset, signature is printed on one line.
+func signature(decl *ast.FuncDecl, fset *token.FileSet, comments []*ast.CommentGroup) string {
+	fn := *decl
+	fn.Doc, fn.Body = nil, nil
+	var node any = &fn
+	if fset == nil {
+		fset = token.NewFileSet()
+	} else {
+		inside := []*ast.CommentGroup{}
+		for _, group := range comments {
+			if group.Pos() >= fn.Pos() && group.End() <= fn.Type.End() {
+				inside = append(inside, group)
+			}
+		}
+		node = &printer.CommentedNode{Node: &fn, Comments: inside}
+	}
+	text := bytes.Buffer{}
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := config.Fprint(&text, fset, node); err != nil {
+		slog.Warn("failed to print signature", "func", decl.Name.Name, "err", err)
+		return "func " + decl.Name.Name
+	}
+	return text.String()
+}
+
+// linkSignature adds links to types in signature, which has been printed from decl by signature.
+// Signature is parsed again, so that positions of types match with text.
+// Types are still resolved from decl, because only it has been type checked.
+func linkSignature(text string, decl *ast.FuncDecl, res *resolver) string {
+	const header = "package p\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", header+text, 0)
+	if err != nil || len(file.Decls) != 1 {
+		slog.Warn("failed to parse signature", "signature", text, "err", err)
+		return text
+	}
+	printed, ok := file.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return text
+	}
+	base := fset.File(file.Pos()).Base() + len(header)
+	refs := typeRefs(printed.Recv, printed.Type.TypeParams, printed.Type.Params, printed.Type.Results)
+	origRefs := typeRefs(decl.Recv, decl.Type.TypeParams, decl.Type.Params, decl.Type.Results)
+	if len(origRefs) != len(refs) {
+		origRefs = refs
+	}
+	linked := strings.Builder{}
+	last := 0
+	for idx, ref := range refs {
+		start, end := int(ref.Pos())-base, int(ref.End())-base
+		linked.WriteString(text[last:start])
+		linked.WriteString(res.refLink(origRefs[idx], text[start:end]))
+		last = end
+	}
+	linked.WriteString(text[last:])
+	return linked.String()
+}
+
+// typeRefs returns type names (e.g. `T` and `io.Reader`) used by given fields in source order.
+// Names of parameters, results and fields are skipped, so are array lengths.
+func typeRefs(fieldLists ...*ast.FieldList) []ast.Expr {
+	refs := []ast.Expr{}
+	var visit func(ast.Node) bool
+	visit = func(node ast.Node) bool {
+		switch t := node.(type) {
+		case *ast.Field:
+			ast.Inspect(t.Type, visit)
+			return false
+		case *ast.ArrayType:
+			ast.Inspect(t.Elt, visit)
+			return false
+		case *ast.SelectorExpr:
+			if _, ok := t.X.(*ast.Ident); ok {
+				refs = append(refs, t)
+			}
+			return false
+		case *ast.Ident:
+			refs = append(refs, t)
+		}
+		return true
+	}
+	for _, fields := range fieldLists {
+		if fields != nil {
+			ast.Inspect(fields, visit)
+		}
+	}
+	return refs
+}
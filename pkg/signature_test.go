@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSignature verifies that receivers, named results and grouped, variadic and multi-line parameters are rendered.
+func TestSignature(t *testing.T) {
+	dir := writeModule(t, map[string]string{"sig.go": `// Package sig has various signatures.
+package sig
+
+import "io"
+
+// Reader reads.
+type Reader struct{}
+
+// Read has named results.
+func (r *Reader) Read(p []byte) (n int, err error) { return 0, nil }
+
+// Copy has grouped parameters.
+func Copy(dst, src io.Writer, buf ...[]byte) (written int64, err error) { return 0, nil }
+
+// Long has parameters on separate lines.
+func Long(
+	a int, // first
+	b Reader,
+) error {
+	return nil
+}
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"func (r *<a href=\"#type-reader\">Reader</a>) Read(p []byte) (n int, err error)\n",
+		"func Copy(dst, src <a href=\"https://pkg.go.dev/io#Writer\">io.Writer</a>, buf ...[]byte) (written int64, err error)\n",
+		"func Long(\n\ta int, // first\n\tb <a href=\"#type-reader\">Reader</a>,\n) error\n",
+		"- [func Long(a int, b Reader) error](#func-long)\n",
+		"    - [func (r *Reader) Read(p \\[\\]byte) (n int, err error)](#func-r-reader-read)\n",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"path/filepath"
+)
+
+// sourceImporter type checks imported packages from their source files, so no compiled
+// packages are needed. Unlike importer.ForCompiler(fset, "source", nil),
+// it finds packages relative to module directory instead of current working directory.
+// In module mode, build.Context.Import finds packages with `go list`, so go command and GOROOT
+// are needed at run time and missing modules might be downloaded.
+type sourceImporter struct {
+	ctxt     build.Context
+	fset     *token.FileSet // positions of imported objects are not used
+	packages map[string]*types.Package
+	warned   bool // failed import has been logged as warning
+}
+
+// sourceImporters shares importers by module directory between packages of one
+// RunDirectory or RunDirTree call, so that imported packages are type checked only once per module.
+type sourceImporters map[string]*sourceImporter
+
+// forModule sets importer of module, creating it if needed.
+func (importers sourceImporters) forModule(mod *module) {
+	imp, ok := importers[mod.dir]
+	if !ok {
+		imp = newSourceImporter(mod.dir)
+		importers[mod.dir] = imp
+	}
+	mod.importer = imp
+}
+
+func newSourceImporter(dir string) *sourceImporter {
+	ctxt := build.Default
+	ctxt.Dir, _ = filepath.Abs(dir)
+	ctxt.CgoEnabled = false
+	return &sourceImporter{ctxt: ctxt, fset: token.NewFileSet(), packages: map[string]*types.Package{}}
+}
+
+func (imp *sourceImporter) Import(path string) (*types.Package, error) {
+	return imp.ImportFrom(path, imp.ctxt.Dir, 0)
+}
+
+// ImportFrom returns type checked package. Type errors within imported package are ignored.
+func (imp *sourceImporter) ImportFrom(path, dir string, _ types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("sourceImporter.ImportFrom failed: %w", err)
+	}
+	bp, err := imp.ctxt.Import(path, dir, 0)
+	if err != nil {
+		if !imp.warned {
+			imp.warned = true
+			slog.Warn("failed to import packages, so links to them are guessed", "path", path, "err", err)
+		}
+		return nil, fmt.Errorf("sourceImporter.ImportFrom failed: %w", err)
+	}
+	if pkg, ok := imp.packages[bp.ImportPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", bp.ImportPath)
+		}
+		return pkg, nil
+	}
+	imp.packages[bp.ImportPath] = nil
+	files := []*ast.File{}
+	for _, fname := range bp.GoFiles {
+		file, err := parser.ParseFile(imp.fset, filepath.Join(bp.Dir, fname), nil, parser.SkipObjectResolution)
+		if err != nil {
+			delete(imp.packages, bp.ImportPath)
+			return nil, fmt.Errorf("sourceImporter.ImportFrom failed: %w", err)
+		}
+		files = append(files, file)
+	}
+	conf := types.Config{Importer: imp, IgnoreFuncBodies: true, Error: func(error) {}}
+	pkg, _ := conf.Check(bp.ImportPath, imp.fset, files, nil)
+	imp.packages[bp.ImportPath] = pkg
+	return pkg, nil
+}
+
+// checkTypes type checks package and returns it with information, which object each identifier refers to.
+// Type errors (e.g. missing dependencies) are only logged, since partial information is still useful.
+func checkTypes(fset *token.FileSet, mod *module, importPath string, astPkg *ast.Package) (*types.Package, *types.Info) {
+	files := packageFiles(astPkg)
+	info := &types.Info{
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+		Types: map[ast.Expr]types.TypeAndValue{},
+	}
+	if mod.importer == nil {
+		mod.importer = newSourceImporter(mod.dir)
+	}
+	conf := types.Config{
+		Importer: mod.importer,
+		Error: func(err error) {
+			slog.Debug("type check failed", "package", importPath, "err", err)
+		},
+	}
+	pkg, _ := conf.Check(importPath, fset, files, info)
+	return pkg, info
+}
@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestImportsPerRun verifies that imported packages are type checked again on every run.
+func TestImportsPerRun(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "// Package a has limit.\npackage a\n\n// Limit is limit.\nconst Limit = 1\n",
+		"b/b.go": "// Package b uses limit.\npackage b\n\nimport \"example.com/mod/a\"\n\n" +
+			"// Size is size.\ntype Size int\n\n// Max is max.\nconst Max Size = a.Limit\n",
+	})
+	for _, limit := range []string{"1", "2"} {
+		content := "// Package a has limit.\npackage a\n\n// Limit is limit.\nconst Limit = " + limit + "\n"
+		if err := os.WriteFile(dir+"/a/a.go", []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var wc writeCloser
+		if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir + "/b"}, "test", true); err != nil {
+			t.Fatal(err)
+		}
+		if expected := "| Max | <code>" + limit + "</code> |"; !strings.Contains(wc.String(), expected) {
+			t.Errorf("%#v is missing from %s", expected, wc.String())
+		}
+	}
+}
+
+// TestFailedImports verifies that failed imports are warned once and links to their types are guessed.
+func TestFailedImports(t *testing.T) {
+	dir := writeModule(t, map[string]string{"c.go": `// Package c uses missing packages.
+package c
+
+import (
+	"example.com/missing/other"
+	"example.com/missing/thing"
+)
+
+// Config embeds missing type.
+type Config struct {
+	thing.Thing
+	Other other.Other
+}
+`})
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<a href="https://pkg.go.dev/example.com/missing/thing#Thing">thing.Thing</a>`,
+		`<a href="https://pkg.go.dev/example.com/missing/other#Other">other.Other</a>`,
+	} {
+		if !strings.Contains(wc.String(), expected) {
+			t.Errorf("%#v is missing from %s", expected, wc.String())
+		}
+	}
+	if count := strings.Count(logs.String(), "failed to import packages"); count != 1 {
+		t.Errorf("failed imports should be warned once instead of %d times: %s", count, logs.String())
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"log/slog"
 	"strings"
 )
 
@@ -26,6 +25,11 @@ func join(elems []varTypeOutput, sep string) varTypeOutput {
 	}
 }
 
+// plain returns text as is in both plain text and markdown.
+func plain(text string) varTypeOutput {
+	return varTypeOutput{plainText: text, markdown: text}
+}
+
 func sprintf(format string, elems ...varTypeOutput) varTypeOutput {
 	plainText := []any{}
 	markdown := []any{}
@@ -39,13 +43,11 @@ func sprintf(format string, elems ...varTypeOutput) varTypeOutput {
 	}
 }
 
+// prefix adds prefixText (e.g. "*" or "[]") in front of type. Links are kept on type name only,
+// the same way as in function signatures.
 func (vto *varTypeOutput) prefix(prefixText string) varTypeOutput {
 	vto.plainText = prefixText + vto.plainText
-	if strings.HasPrefix(vto.markdown, "<a href=") {
-		vto.markdown = strings.Replace(vto.markdown, `">`, `">`+prefixText, 1)
-	} else {
-		vto.markdown = prefixText + vto.markdown
-	}
+	vto.markdown = prefixText + vto.markdown
 	return *vto
 }
 
@@ -54,19 +56,20 @@ func (vto *varTypeOutput) replace(old, new string, n int) {
 	vto.markdown = strings.Replace(vto.markdown, old, new, n)
 }
 
-func variableType(variable ast.Expr, depth int, hyphen bool, imports map[string]string) varTypeOutput {
+func variableType(variable ast.Expr, depth int, hyphen bool, res *resolver) varTypeOutput {
 	switch t := variable.(type) {
 	case nil:
 		return sprintf("nil")
 	case *ast.ArrayType:
-		varType := variableType(t.Elt, depth, hyphen, imports)
-		return varType.prefix("[]")
+		varType := variableType(t.Elt, depth, hyphen, res)
+		if t.Len == nil {
+			return varType.prefix("[]")
+		}
+		length := variableType(t.Len, depth, hyphen, res)
+		return varType.prefix("[" + length.plainText + "]")
 	case *ast.BasicLit:
 		if t.Value != "" {
-			return varTypeOutput{
-				plainText: t.Value,
-				markdown:  fmt.Sprintf(`<a href="#%s">%s</a>`, intoLink("type "+t.Value), t.Value),
-			}
+			return plain(t.Value)
 		}
 		switch t.Kind {
 		case token.INT:
@@ -80,56 +83,90 @@ func variableType(variable ast.Expr, depth int, hyphen bool, imports map[string]
 		case token.STRING:
 			return sprintf("string")
 		default:
-			panic(fmt.Errorf("unknown token kind t.Kind=%#v", t.Kind))
+			return res.sourceText(t)
 		}
+	case *ast.BinaryExpr:
+		x := variableType(t.X, depth, hyphen, res)
+		y := variableType(t.Y, depth, hyphen, res)
+		return sprintf("%s %s %s", x, plain(t.Op.String()), y)
 	case *ast.CallExpr:
-		funcName := variableType(t.Fun, depth, hyphen, imports).plainText
+		funcName := variableType(t.Fun, depth, hyphen, res).plainText
 		varTypes := []varTypeOutput{}
 		for _, arg := range t.Args {
-			varTypes = append(varTypes, variableType(arg, depth, hyphen, imports))
+			varTypes = append(varTypes, variableType(arg, depth, hyphen, res))
 		}
 		return sprintf(funcName+"(%s)", join(varTypes, ", "))
 	case *ast.CompositeLit:
-		eltsType := variableType(t.Type, depth, hyphen, imports)
+		eltsType := variableType(t.Type, depth, hyphen, res)
 		varTypes := []varTypeOutput{}
 		for _, elt := range t.Elts {
-			varTypes = append(varTypes, variableType(elt, depth, hyphen, imports))
+			varTypes = append(varTypes, variableType(elt, depth, hyphen, res))
 		}
-		switch subType := t.Type.(type) {
-		case *ast.ArrayType, *ast.MapType, *ast.SelectorExpr:
+		switch {
+		case t.Type == nil:
+			return join(varTypes, ",\n")
+		case len(varTypes) == 0:
+			return plain(eltsType.plainText + "{}")
+		default:
 			vto := join(varTypes, ",\n")
 			vto.replace("\n", "\n"+basePrefix, -1)
-			msg := fmt.Sprintf("%s{\n%s%%s,\n}", eltsType.plainText, basePrefix)
+			// type is used in format, so '%' in it (e.g. array length) must be escaped
+			msg := fmt.Sprintf("%s{\n%s%%s,\n}", strings.ReplaceAll(eltsType.plainText, "%", "%%"), basePrefix)
 			return sprintf(msg, vto)
-		case nil:
-			return join(varTypes, ",\n")
+		}
+	case *ast.ChanType:
+		vto := variableType(t.Value, depth, hyphen, res)
+		switch t.Dir {
+		case ast.SEND:
+			return vto.prefix("chan<- ")
+		case ast.RECV:
+			return vto.prefix("<-chan ")
 		default:
-			panic(fmt.Errorf("Unknown CompositeLit: %#v", subType))
+			return vto.prefix("chan ")
 		}
 	case *ast.Ellipsis:
-		return sprintf("...%s", variableType(t.Elt, depth, hyphen, imports))
+		if t.Elt == nil { // [...]T
+			return sprintf("...")
+		}
+		return sprintf("...%s", variableType(t.Elt, depth, hyphen, res))
+	case *ast.FuncLit:
+		vtoParams := funcParams(t.Type.Params, res)
+		vtoReturns := funcReturns(t.Type.Results, res)
+		return sprintf("func(%s)%s {...}", vtoParams, vtoReturns)
 	case *ast.FuncType:
-		vtoParams := funcParams(t.Params, imports)
-		vtoReturns := funcReturns(t.Results, imports)
+		vtoParams := funcParams(t.Params, res)
+		vtoReturns := funcReturns(t.Results, res)
 		return sprintf("func(%s)%s", vtoParams, vtoReturns)
 	case *ast.Ident:
-		switch t.Name {
-		case "bool", "byte", "char", "error", "float", "float32", "float64", "int", "int32", "int64", "string":
-			return sprintf(t.Name)
-		}
-		if exportedType.MatchString(t.Name) {
-			return varTypeOutput{
-				plainText: t.Name,
-				markdown:  fmt.Sprintf(`<a href="#%s">%s</a>`, intoLink("type "+t.Name), t.Name),
-			}
+		return varTypeOutput{plainText: t.Name, markdown: res.refLink(t, t.Name)}
+	case *ast.IndexExpr:
+		return sprintf("%s[%s]", variableType(t.X, depth, hyphen, res), variableType(t.Index, depth, hyphen, res))
+	case *ast.IndexListExpr:
+		indices := []varTypeOutput{}
+		for _, index := range t.Indices {
+			indices = append(indices, variableType(index, depth, hyphen, res))
 		}
-		slog.Warn("Internal type: " + t.Name)
-		return sprintf(t.Name)
+		return sprintf("%s[%s]", variableType(t.X, depth, hyphen, res), join(indices, ", "))
 	case *ast.InterfaceType:
-		return sprintf("interface{}")
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return sprintf("interface{}")
+		}
+		// inline interface, e.g. constraint in type parameters
+		elems := []varTypeOutput{}
+		for _, field := range t.Methods.List {
+			switch ft := field.Type.(type) {
+			case *ast.FuncType:
+				elems = append(elems, sprintf(
+					field.Names[0].Name+"(%s)%s", funcParams(ft.Params, res), funcReturns(ft.Results, res),
+				))
+			default:
+				elems = append(elems, variableType(ft, depth, hyphen, res))
+			}
+		}
+		return sprintf("interface{ %s }", join(elems, "; "))
 	case *ast.KeyValueExpr:
-		keyType := variableType(t.Key, depth, hyphen, imports)
-		valueType := variableType(t.Value, depth, hyphen, imports)
+		keyType := variableType(t.Key, depth, hyphen, res)
+		valueType := variableType(t.Value, depth, hyphen, res)
 		switch t.Value.(type) {
 		case *ast.CompositeLit:
 			switch t.Key.(type) {
@@ -141,34 +178,55 @@ func variableType(variable ast.Expr, depth int, hyphen bool, imports map[string]
 		}
 		return sprintf("%s: %s", keyType, valueType)
 	case *ast.MapType:
-		keyType := variableType(t.Key, depth, hyphen, imports)
-		valueType := variableType(t.Value, depth, hyphen, imports)
+		keyType := variableType(t.Key, depth, hyphen, res)
+		valueType := variableType(t.Value, depth, hyphen, res)
 		return sprintf("map[%s]%s", keyType, valueType)
+	case *ast.ParenExpr:
+		return sprintf("(%s)", variableType(t.X, depth, hyphen, res))
 	case *ast.SelectorExpr:
+		if _, ok := t.X.(*ast.Ident); !ok { // e.g. field of returned value
+			return sprintf("%s."+t.Sel.Name, variableType(t.X, depth, hyphen, res))
+		}
 		msg := fmt.Sprintf("%s.%s", t.X, t.Sel)
-		return varTypeOutput{plainText: msg, markdown: intoImportLink(msg, imports)}
+		return varTypeOutput{plainText: msg, markdown: res.refLink(t, msg)}
+	case *ast.SliceExpr:
+		indices := []varTypeOutput{}
+		for _, index := range []ast.Expr{t.Low, t.High, t.Max} {
+			if index == nil {
+				indices = append(indices, sprintf(""))
+				continue
+			}
+			indices = append(indices, variableType(index, depth, hyphen, res))
+		}
+		if !t.Slice3 {
+			indices = indices[:2]
+		}
+		return sprintf("%s[%s]", variableType(t.X, depth, hyphen, res), join(indices, ":"))
 	case *ast.StarExpr:
-		vto := variableType(t.X, depth, hyphen, imports)
+		vto := variableType(t.X, depth, hyphen, res)
 		return vto.prefix("*")
 	case *ast.StructType:
+		if len(t.Fields.List) == 0 {
+			return sprintf("struct{}")
+		}
 		varTypes := []varTypeOutput{}
 		for _, field := range t.Fields.List {
-			varTypes = append(varTypes, typeField(field, depth+1, hyphen, imports))
+			varTypes = append(varTypes, typeField(field, depth+1, hyphen, res))
 		}
 		vto := join(varTypes, "\n")
 		if hyphen {
 			return sprintf("struct\n%s", vto)
 		}
 		return sprintf("struct {\n%s\n}", vto)
-	case *ast.UnaryExpr:
-		switch t.Op {
-		case token.AND:
-			vto := variableType(t.X, depth, hyphen, imports)
-			return vto.prefix("&")
-		default:
-			panic(fmt.Errorf("unknown unary type %d for %s", t.Op, t.X))
+	case *ast.TypeAssertExpr:
+		if t.Type == nil { // x.(type) in type switch
+			return sprintf("%s.(type)", variableType(t.X, depth, hyphen, res))
 		}
+		return sprintf("%s.(%s)", variableType(t.X, depth, hyphen, res), variableType(t.Type, depth, hyphen, res))
+	case *ast.UnaryExpr:
+		vto := variableType(t.X, depth, hyphen, res)
+		return vto.prefix(t.Op.String())
 	default:
-		panic(fmt.Errorf("unknown variable type variable.(type)=%#v", variable))
+		return res.sourceText(variable)
 	}
 }
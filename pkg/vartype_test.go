@@ -0,0 +1,146 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerics verifies that type parameters, constraints and instantiated types are rendered.
+func TestGenerics(t *testing.T) {
+	dir := writeModule(t, map[string]string{"gen.go": `// Package gen has generics.
+package gen
+
+import "io"
+
+// Number is a constraint.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Set is a generic set.
+type Set[K comparable] struct {
+	items map[K]struct{}
+}
+
+// Pair has two values.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// New creates Set.
+func New[K comparable]() *Set[K] { return nil }
+
+// Add adds item.
+func (s *Set[K]) Add(item K) {}
+
+// Keys returns keys.
+func Keys[K comparable, V any](m map[K]V) []K { return nil }
+
+// Sum sums.
+func Sum[T Number](values ...T) T { var t T; return t }
+
+// Pairs returns pairs.
+func Pairs(s Set[string], r io.Reader) []Pair[string, io.Reader] { return nil }
+
+// Inline has inline constraint.
+func Inline[T interface{ ~int | ~string }](t T) {}
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"- [func Keys\\[K comparable, V any\\](m map\\[K\\]V) \\[\\]K](#func-keys)\n",
+		"    - [func (s *Set\\[K\\]) Add(item K)](#func-s-setk-add)\n",
+		"### func (s *Set[K]) [Add](./gen.go#L26)\n",
+		"func Inline[T interface{ ~int | ~string }](t T)\n",
+		`func Sum[T <a href="#type-number">Number</a>](values ...T) T`,
+		"type Number interface {\n    ~int | ~int64 | ~float64\n}",
+		"type Pair[K comparable, V any] struct {\n    Key K\n    Value V\n}",
+		`[]<a href="#type-pair">Pair</a>[string, <a href="https://pkg.go.dev/io#Reader">io.Reader</a>]`,
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}
+
+// TestExpressions verifies that all kinds of expressions in declarations are rendered.
+func TestExpressions(t *testing.T) {
+	dir := writeModule(t, map[string]string{"expr.go": `// Package expr has various expressions.
+package expr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Point is a point.
+type Point struct{ X, Y int }
+
+// Flag values.
+const (
+	FlagA = 1 << iota
+	FlagB
+)
+
+// C and D are declared together.
+const C, D = 1, 2
+
+// Variables.
+var (
+	Done    chan<- struct{}
+	Events  <-chan string
+	Queue   = make(chan int, (1 + 2))
+	Origin  = &Point{X: 1}
+	Empty   = Point{}
+	Grid    [3][4]int
+	Days    = [...]string{"mon", "tue"}
+	Tail    = strings.Fields("a b c")[1:]
+	Handler = func(s string) error { return nil }
+	Negated = -FlagB
+	Upper   = strings.NewReplacer("a", "A").Replace
+	Any     interface{} = 1
+	Number  = Any.(int)
+	Str, OK = Any.(string)
+	Err     error = errors.New("oops")
+	Percent = "%d%%"
+	Mods    = [10 % 4]int{}
+	Rests   = [10 % 4]int{1, 2}
+)
+`})
+	var wc writeCloser
+	if err := RunDirectory(OutputSettings{Default: &wc, Directory: dir}, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	received := wc.String()
+	for _, expected := range []string{
+		"FlagA = 1 &lt;&lt; iota",
+		"Done chan&lt;- struct{}",
+		"Events &lt;-chan string",
+		"Queue = make(chan int, (1 + 2))",
+		"Origin = &amp;Point{\n    X: 1,\n}",
+		"Empty = Point{}",
+		"Grid [3][4]int",
+		"Days = [...]string{",
+		"Tail = strings.Fields(\"a b c\")[1:]",
+		"Handler = func(s string) error {...}",
+		"Negated = -FlagB",
+		"Upper = strings.NewReplacer(\"a\", \"A\").Replace",
+		"Number = Any.(int)",
+		"Any interface{} = 1",
+		"Str, OK = Any.(string)",
+		`Err error = errors.New("oops")`,
+		"const C, D = 1, 2\n",
+		`Percent = "%d%%"`,
+		"Mods = [10 % 4]int{}",
+		"Rests = [10 % 4]int{\n    1,\n    2,\n}",
+		"type Point struct {\n    X, Y int\n}",
+	} {
+		if !strings.Contains(received, expected) {
+			t.Errorf("%#v is missing from %s", expected, received)
+		}
+	}
+}